@@ -0,0 +1,79 @@
+package config
+
+import (
+	"context"
+)
+
+// changesBuffer number of changes buffered for each Changes subscriber before further changes are dropped.
+const changesBuffer = 16
+
+// ConfigChange a reload of the container's config, along with what changed.
+type ConfigChange struct {
+	// Files loaded files that were re-read, nil when the change came from polling.
+	Files []string
+	Diff  []KeyChange
+}
+
+// Changes return a channel receiving a ConfigChange on every reload, as an alternative to observers for use
+// in select loops. Sends never block: changes are buffered and dropped if the buffer is full. The channel is
+// closed once ctx is done.
+func (c *Container) Changes(ctx context.Context) <-chan ConfigChange {
+	ch := make(chan ConfigChange, changesBuffer)
+
+	c.mu.Lock()
+	c.changeSubs = append(c.changeSubs, ch)
+	c.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		for i, sub := range c.changeSubs {
+			if sub == ch {
+				c.changeSubs = append(c.changeSubs[:i:i], c.changeSubs[i+1:]...)
+
+				break
+			}
+		}
+		close(ch)
+	}()
+
+	return ch
+}
+
+// changeSnapshot capture the current settings for diffing after a reload, if anyone is listening for changes.
+func (c *Container) changeSnapshot() map[string]interface{} {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if len(c.changeSubs) == 0 {
+		return nil
+	}
+
+	return c.viper.AllSettings()
+}
+
+// publishChange send the difference between before and the current settings to every Changes subscriber.
+func (c *Container) publishChange(files []string, before map[string]interface{}) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if len(c.changeSubs) == 0 {
+		return
+	}
+
+	if before == nil {
+		before = make(map[string]interface{})
+	}
+
+	change := ConfigChange{Files: files, Diff: diffSettings(before, c.viper.AllSettings())}
+	for _, ch := range c.changeSubs {
+		select {
+		case ch <- change:
+		default:
+			c.logger.Warn("config change dropped, subscriber is not keeping up")
+		}
+	}
+}
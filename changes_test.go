@@ -0,0 +1,50 @@
+package config_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/phpboyscout/config"
+)
+
+func TestContainer_Changes(t *testing.T) {
+	t.Parallel()
+	logger := log.New(io.Discard)
+	file := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(file, []byte(firstMockFilesYaml), 0o600))
+
+	c := config.NewFilesContainer(logger, afero.NewOsFs(), file)
+	t.Cleanup(func() { _ = c.Close() })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changes := c.Changes(ctx)
+
+	require.NoError(t, os.WriteFile(file, []byte("yaml:\n  key: \"updated\"\n  bool: true\n  int: 1\n  float: 2.4\n  time: \"2021-09-11 12:34:56\"\n  duration: 5s\n  added: yes"), 0o600))
+
+	select {
+	case change := <-changes:
+		assert.Equal(t, []string{file}, change.Files)
+		assert.Equal(t, []config.KeyChange{
+			{Key: "yaml.added", New: "yes"},
+			{Key: "yaml.key", Old: "value", New: "updated"},
+		}, change.Diff)
+	case <-time.After(2 * time.Second):
+		t.Fatal("no change received")
+	}
+
+	cancel()
+	assert.Eventually(t, func() bool {
+		_, open := <-changes
+
+		return !open
+	}, time.Second, 10*time.Millisecond)
+}
@@ -1,11 +1,14 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
 	"io"
+	"path/filepath"
 	"strings"
 
 	"github.com/charmbracelet/log"
+	"github.com/go-errors/errors"
 	"github.com/spf13/afero"
 	"github.com/spf13/viper"
 )
@@ -16,37 +19,185 @@ func initContainer(l *log.Logger, fs afero.Fs) *Container {
 		viper:     viper.New(),
 		logger:    l.With("component", "config"),
 		observers: make([]Observable, 0),
+		fs:        fs,
 	}
 
 	c.viper.SetFs(fs)
-	c.viper.AutomaticEnv()
-	c.viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
-	c.viper.SetTypeByDefaultValue(true)
+	configureViper(c.viper)
 
 	return &c
 }
 
+// configureViper apply the environment and typing behaviour shared by every container's viper.
+func configureViper(v *viper.Viper) {
+	v.AutomaticEnv()
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.SetTypeByDefaultValue(true)
+}
+
 // NewFilesContainer Initialise configuration container to read files from the FS.
 func NewFilesContainer(l *log.Logger, fs afero.Fs, configFiles ...string) *Container {
 	c := initContainer(l, fs)
+	c.readFiles(configFiles...)
+
+	return c
+}
+
+// MergePriority order in which overlapping keys from multiple config files take precedence.
+type MergePriority int
+
+const (
+	// LastWins later files override earlier ones, as with NewFilesContainer.
+	LastWins MergePriority = iota
+	// FirstWins earlier files override later ones; later files only fill keys the earlier ones lack.
+	FirstWins
+)
+
+// NewFilesContainerWithPriority Initialise configuration container to read files from the FS, resolving
+// overlapping keys according to priority.
+func NewFilesContainerWithPriority(l *log.Logger, fs afero.Fs, priority MergePriority, configFiles ...string) *Container {
+	if priority != FirstWins {
+		return NewFilesContainer(l, fs, configFiles...)
+	}
+
+	reversed := make([]string, len(configFiles))
+	for i, f := range configFiles {
+		reversed[len(configFiles)-1-i] = f
+	}
+
+	c := NewFilesContainer(l, fs, reversed...)
+	if len(configFiles) > 0 {
+		// readFiles names the container after the files in merge order; relative paths still resolve
+		// against the highest-priority file.
+		c.mu.Lock()
+		c.ID = strings.Join(configFiles, ";")
+		c.baseDir = filepath.Dir(configFiles[0])
+		c.mu.Unlock()
+	}
+
+	return c
+}
+
+// readFiles read the given files into the container, later files taking precedence, and start watching them.
+func (c *Container) readFiles(configFiles ...string) {
+	loaded := make([]string, 0, len(configFiles))
 
 	if len(configFiles) > 0 {
 		c.ID = configFiles[0]
-		c.viper.SetConfigFile(configFiles[0])
-		c.handleReadFileError(c.viper.ReadInConfig())
+		c.baseDir = filepath.Dir(configFiles[0])
+		if c.skipEmptyFile(configFiles[0]) {
+			loaded = append(loaded, configFiles[0])
+		} else {
+			err := readConfigFile(c.viper, c.fs, configFiles[0], false)
+			c.handleReadFileError(configFiles[0], err)
+			if err == nil {
+				c.retainRawFile(configFiles[0])
+				loaded = append(loaded, configFiles[0])
+			}
+		}
 	}
 
 	if len(configFiles) > 1 {
 		for _, f := range configFiles[1:] {
 			c.ID = fmt.Sprintf("%s;%s", c.ID, f)
-			c.viper.SetConfigFile(f)
-			c.handleReadFileError(c.viper.MergeInConfig())
+			if c.skipEmptyFile(f) {
+				loaded = append(loaded, f)
+
+				continue
+			}
+			err := readConfigFile(c.viper, c.fs, f, true)
+			c.handleReadFileError(f, err)
+			if err == nil {
+				c.retainRawFile(f)
+				loaded = append(loaded, f)
+			}
 		}
+	}
+
+	if len(loaded) > 0 {
+		// leave viper pointing at a file that was read rather than whichever candidate was tried last.
+		c.viper.SetConfigFile(loaded[len(loaded)-1])
+	}
+	c.loadedFiles = loaded
+
+	if len(configFiles) > 0 {
+		c.files = configFiles
 		c.logger.Info("Loaded Config")
 		c.watchConfig()
 	}
+}
 
-	return c
+// ConfigFileUsed return the config file viper last read, which for containers built from several files is
+// the last one that existed. An empty string is returned for containers not built from files.
+func (c *Container) ConfigFileUsed() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.viper.ConfigFileUsed()
+}
+
+// ConfigFilesLoaded return the config files merged into the container, in merge order. Candidate paths
+// that didn't exist, or failed to parse, are omitted. The list is refreshed on reload.
+func (c *Container) ConfigFilesLoaded() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return append([]string(nil), c.loadedFiles...)
+}
+
+// ErrIncludeCycle returned when config files include each other in a loop.
+var ErrIncludeCycle = errors.New("config include cycle detected")
+
+// NewFilesContainerWithIncludes Initialise configuration container to read files from the FS, resolving
+// any `include: [...]` lists. Included files are read relative to the including file's directory and merged
+// before the including file's own keys.
+func NewFilesContainerWithIncludes(l *log.Logger, fs afero.Fs, configFiles ...string) (*Container, error) {
+	ordered := make([]string, 0, len(configFiles))
+	seen := make(map[string]bool)
+
+	for _, f := range configFiles {
+		if err := resolveIncludes(fs, filepath.Clean(f), make([]string, 0), seen, &ordered); err != nil {
+			return nil, err
+		}
+	}
+
+	return NewFilesContainer(l, fs, ordered...), nil
+}
+
+// resolveIncludes append the includes of file, depth first, followed by file itself to ordered.
+// stack holds the chain of files currently being resolved and is used to detect cycles.
+func resolveIncludes(fs afero.Fs, file string, stack []string, seen map[string]bool, ordered *[]string) error {
+	for _, s := range stack {
+		if s == file {
+			return errors.Errorf("%w: %s", ErrIncludeCycle, strings.Join(append(stack, file), " -> "))
+		}
+	}
+
+	if seen[file] {
+		return nil
+	}
+
+	v := viper.New()
+	v.SetFs(fs)
+	v.SetConfigFile(file)
+	if err := v.ReadInConfig(); err != nil {
+		return errors.WrapPrefix(err, "unable to read config file "+file, 0)
+	}
+
+	for _, include := range v.GetStringSlice("include") {
+		if !filepath.IsAbs(include) {
+			include = filepath.Join(filepath.Dir(file), include)
+		}
+
+		if err := resolveIncludes(fs, filepath.Clean(include), append(stack, file), seen, ordered); err != nil {
+			return err
+		}
+	}
+
+	seen[file] = true
+	*ordered = append(*ordered, file)
+
+	return nil
 }
 
 // NewReaderContainer Initialise configuration container to read config from ioReader.
@@ -57,16 +208,71 @@ func NewReaderContainer(l *log.Logger, format string, configReaders ...io.Reader
 
 	if len(configReaders) > 0 {
 		c.ID = "0"
-		c.handleReadFileError(c.viper.ReadConfig(configReaders[0]))
+		if b := c.nonEmptyReader("reader 0", configReaders[0]); b != nil {
+			c.handleReadFileError("reader 0", c.viper.ReadConfig(bytes.NewReader(b)))
+			c.retainRaw("reader 0", format, b)
+		}
 	}
 
 	if len(configReaders) > 1 {
 		for i, f := range configReaders[1:] {
 			c.ID = fmt.Sprintf("%s;%d", c.ID, i+1)
-			c.handleReadFileError(c.viper.MergeConfig(f))
+			source := fmt.Sprintf("reader %d", i+1)
+			if b := c.nonEmptyReader(source, f); b != nil {
+				c.handleReadFileError(source, c.viper.MergeConfig(bytes.NewReader(b)))
+				c.retainRaw(source, format, b)
+			}
 		}
 		c.logger.Info("Loaded Config")
 	}
 
 	return c
 }
+
+// NewStringContainer Initialise configuration container to read config from a string, e.g. for defaults
+// held in code or tests.
+func NewStringContainer(l *log.Logger, format, content string) *Container {
+	return NewStringsContainer(l, format, content)
+}
+
+// NewStringsContainer Initialise configuration container to read config from strings, later strings taking
+// precedence.
+func NewStringsContainer(l *log.Logger, format string, contents ...string) *Container {
+	readers := make([]io.Reader, len(contents))
+	for i, content := range contents {
+		readers[i] = strings.NewReader(content)
+	}
+
+	return NewReaderContainer(l, format, readers...)
+}
+
+// skipEmptyFile reports whether f exists but holds nothing beyond whitespace, in which case merging it
+// would be a no-op and it is skipped.
+func (c *Container) skipEmptyFile(f string) bool {
+	b, err := afero.ReadFile(c.fs, f)
+	if err != nil || len(bytes.TrimSpace(b)) > 0 {
+		return false
+	}
+
+	c.logger.Debug("config file is empty, skipping", "file", f)
+
+	return true
+}
+
+// nonEmptyReader buffer r, returning nil if it holds nothing beyond whitespace or can't be read.
+func (c *Container) nonEmptyReader(source string, r io.Reader) []byte {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		c.handleReadFileError(source, err)
+
+		return nil
+	}
+
+	if len(bytes.TrimSpace(b)) == 0 {
+		c.logger.Debug("config reader is empty, skipping", "reader", source)
+
+		return nil
+	}
+
+	return b
+}
@@ -2,8 +2,10 @@ package config_test
 
 import (
 	"io"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/charmbracelet/log"
 	"github.com/spf13/afero"
@@ -59,6 +61,217 @@ func TestNewFilesContainer(t *testing.T) {
 	})
 }
 
+func TestContainer_ConfigFilesLoaded(t *testing.T) {
+	t.Parallel()
+	l := log.New(io.Discard)
+
+	t.Run("with existing and missing files", func(t *testing.T) {
+		t.Parallel()
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, "base.yml", []byte(firstMockFilesYaml), 0o644))
+		require.NoError(t, afero.WriteFile(fs, "override.yml", []byte(secondMockFilesYaml), 0o644))
+
+		c := config.NewFilesContainer(l, fs, "base.yml", "override.yml", "local.yml")
+
+		assert.Equal(t, []string{"base.yml", "override.yml"}, c.ConfigFilesLoaded())
+		assert.Equal(t, "override.yml", c.ConfigFileUsed())
+	})
+
+	t.Run("with a malformed file", func(t *testing.T) {
+		t.Parallel()
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, "base.yml", []byte(firstMockFilesYaml), 0o644))
+		require.NoError(t, afero.WriteFile(fs, "broken.yml", []byte("key: [unclosed"), 0o644))
+
+		c := config.NewFilesContainer(l, fs, "base.yml", "broken.yml")
+
+		assert.Equal(t, []string{"base.yml"}, c.ConfigFilesLoaded())
+	})
+
+	t.Run("with a reader", func(t *testing.T) {
+		t.Parallel()
+		c := config.NewStringContainer(l, "yaml", firstMockFilesYaml)
+
+		assert.Empty(t, c.ConfigFilesLoaded())
+		assert.Empty(t, c.ConfigFileUsed())
+	})
+}
+
+func TestNewFilesContainerWithPriority(t *testing.T) {
+	t.Parallel()
+	logger := log.New(io.Discard)
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "first.yml", []byte(firstMockFilesYaml), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "second.yml", []byte(secondMockFilesYaml), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "etc/app/first.yml", []byte("cert: certs/ca.pem"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "usr/share/app/defaults.yml", []byte("cert: other.pem"), 0o644))
+
+	t.Run("with last wins", func(t *testing.T) {
+		t.Parallel()
+		c := config.NewFilesContainerWithPriority(logger, fs, config.LastWins, "first.yml", "second.yml")
+		assert.Equal(t, "value2", c.GetString("yaml.key"))
+		assert.True(t, c.GetBool("yaml.bool"))
+		assert.Equal(t, "secondfile", c.GetString("yaml.more.key2"))
+	})
+
+	t.Run("with first wins", func(t *testing.T) {
+		t.Parallel()
+		c := config.NewFilesContainerWithPriority(logger, fs, config.FirstWins, "first.yml", "second.yml")
+		assert.Equal(t, "value", c.GetString("yaml.key"))
+		assert.True(t, c.GetBool("yaml.bool"))
+		assert.Equal(t, "secondfile", c.GetString("yaml.more.key2"))
+	})
+
+	t.Run("with first wins resolving paths", func(t *testing.T) {
+		t.Parallel()
+		c := config.NewFilesContainerWithPriority(logger, fs, config.FirstWins, "etc/app/first.yml", "usr/share/app/defaults.yml")
+		assert.Equal(t, filepath.Join("etc/app", "certs/ca.pem"), c.GetPath("cert"))
+		assert.Equal(t, "etc/app/first.yml;usr/share/app/defaults.yml", c.ID)
+	})
+}
+
+func TestNewFilesContainer_YAMLAnchors(t *testing.T) {
+	t.Parallel()
+	logger := log.New(io.Discard)
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "anchors.yml", []byte(`defaults: &d
+  timeout: 5s
+  retries: 3
+  tls:
+    enabled: true
+extra: &e
+  region: eu
+hosts: &h [a, b]
+prod:
+  <<: *d
+  retries: 5
+multi:
+  <<: [*d, *e]
+replicas: *h`), 0o644))
+
+	c := config.NewFilesContainer(logger, fs, "anchors.yml")
+	require.False(t, c.HasLoadErrors())
+
+	assert.Equal(t, 5*time.Second, c.GetDuration("prod.timeout"))
+	assert.Equal(t, 5, c.GetInt("prod.retries"))
+	assert.True(t, c.GetBool("prod.tls.enabled"))
+	assert.Equal(t, 3, c.GetInt("multi.retries"))
+	assert.Equal(t, "eu", c.GetString("multi.region"))
+	assert.Equal(t, []string{"a", "b"}, c.GetStringSlice("replicas"))
+}
+
+func TestNewFilesContainer_LoadErrors(t *testing.T) {
+	t.Parallel()
+	logger := log.New(io.Discard)
+
+	t.Run("with malformed file", func(t *testing.T) {
+		t.Parallel()
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, "first.yml", []byte(firstMockFilesYaml), 0o644))
+		require.NoError(t, afero.WriteFile(fs, "broken.yml", []byte("yaml: [unclosed"), 0o644))
+
+		c := config.NewFilesContainer(logger, fs, "first.yml", "broken.yml")
+		assert.True(t, c.HasLoadErrors())
+		require.Len(t, c.LoadErrors(), 1)
+		assert.Contains(t, c.LoadErrors()[0].Error(), "broken.yml")
+		assert.Equal(t, "value", c.GetString("yaml.key"))
+	})
+
+	t.Run("with missing file", func(t *testing.T) {
+		t.Parallel()
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, "first.yml", []byte(firstMockFilesYaml), 0o644))
+
+		c := config.NewFilesContainer(logger, fs, "first.yml", "missing.yml")
+		assert.False(t, c.HasLoadErrors())
+		assert.Empty(t, c.LoadErrors())
+	})
+}
+
+func TestNewFilesContainer_DeepMerge(t *testing.T) {
+	t.Parallel()
+	logger := log.New(io.Discard)
+	fs := afero.NewMemMapFs()
+
+	files := map[string]string{
+		"base.yml": `app:
+  settings:
+    name: "base"
+    http:
+      port: 8080
+      tls:
+        enabled: false`,
+		"http.yml": `app:
+  settings:
+    http:
+      timeout: 5s
+      tls:
+        cert: "/etc/tls.pem"`,
+		"tls.yml": `app:
+  settings:
+    http:
+      tls:
+        enabled: true
+        ciphers:
+          preferred: "TLS_AES_128_GCM_SHA256"`,
+	}
+	for name, content := range files {
+		require.NoError(t, afero.WriteFile(fs, name, []byte(content), 0o644))
+	}
+
+	c := config.NewFilesContainer(logger, fs, "base.yml", "http.yml", "tls.yml")
+
+	assert.Equal(t, "base", c.GetString("app.settings.name"))
+	assert.Equal(t, 8080, c.GetInt("app.settings.http.port"))
+	assert.Equal(t, "5s", c.GetString("app.settings.http.timeout"))
+	assert.True(t, c.GetBool("app.settings.http.tls.enabled"))
+	assert.Equal(t, "/etc/tls.pem", c.GetString("app.settings.http.tls.cert"))
+	assert.Equal(t, "TLS_AES_128_GCM_SHA256", c.GetString("app.settings.http.tls.ciphers.preferred"))
+	assert.Len(t, c.Sub("app.settings.http.tls").GetViper().AllKeys(), 3)
+}
+
+func TestNewFilesContainerWithIncludes(t *testing.T) {
+	t.Parallel()
+	logger := log.New(io.Discard)
+
+	t.Run("with a chain of includes", func(t *testing.T) {
+		t.Parallel()
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, "conf/app.yml", []byte("include: [base.yml, secrets/secrets.yml]\napp:\n  name: app"), 0o644))
+		require.NoError(t, afero.WriteFile(fs, "conf/base.yml", []byte("include: [defaults.yml]\napp:\n  name: base\n  port: 80"), 0o644))
+		require.NoError(t, afero.WriteFile(fs, "conf/defaults.yml", []byte("app:\n  name: defaults\n  port: 1\n  debug: true"), 0o644))
+		require.NoError(t, afero.WriteFile(fs, "conf/secrets/secrets.yml", []byte("app:\n  password: hunter2"), 0o644))
+
+		c, err := config.NewFilesContainerWithIncludes(logger, fs, "conf/app.yml")
+		require.NoError(t, err)
+		assert.Equal(t, "app", c.GetString("app.name"))
+		assert.Equal(t, 80, c.GetInt("app.port"))
+		assert.True(t, c.GetBool("app.debug"))
+		assert.Equal(t, "hunter2", c.GetString("app.password"))
+	})
+
+	t.Run("with a missing included file", func(t *testing.T) {
+		t.Parallel()
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, "conf/app.yml", []byte("include: [missing.yml]"), 0o644))
+
+		_, err := config.NewFilesContainerWithIncludes(logger, fs, "conf/app.yml")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "missing.yml")
+	})
+
+	t.Run("with a cyclic include", func(t *testing.T) {
+		t.Parallel()
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, "conf/a.yml", []byte("include: [b.yml]"), 0o644))
+		require.NoError(t, afero.WriteFile(fs, "conf/b.yml", []byte("include: [c.yml]"), 0o644))
+		require.NoError(t, afero.WriteFile(fs, "conf/c.yml", []byte("include: [a.yml]"), 0o644))
+
+		_, err := config.NewFilesContainerWithIncludes(logger, fs, "conf/a.yml")
+		require.ErrorIs(t, err, config.ErrIncludeCycle)
+	})
+}
+
 func TestNewReaderContainer(t *testing.T) {
 	t.Parallel()
 	logger := log.New(io.Discard)
@@ -87,4 +300,91 @@ func TestNewReaderContainer(t *testing.T) {
 		value = c.GetString("yaml.more.key2")
 		assert.Equal(t, "secondfile", value)
 	})
+
+	t.Run("with ini config reader", func(t *testing.T) {
+		t.Parallel()
+		c := config.NewReaderContainer(logger, "ini", strings.NewReader("[database]\nhost = db.local\nport = 5432"))
+
+		assert.Equal(t, "db.local", c.GetString("database.host"))
+		assert.Equal(t, 5432, c.GetInt("database.port"))
+	})
+
+	t.Run("with properties config reader", func(t *testing.T) {
+		t.Parallel()
+		c := config.NewReaderContainer(logger, "properties", strings.NewReader("database.host=db.local\ndatabase.port=5432"))
+
+		assert.Equal(t, "db.local", c.GetString("database.host"))
+		assert.Equal(t, 5432, c.GetInt("database.port"))
+	})
+}
+
+func TestNewStringContainer(t *testing.T) {
+	t.Parallel()
+	l := log.New(io.Discard)
+
+	t.Run("with a single string", func(t *testing.T) {
+		t.Parallel()
+		c := config.NewStringContainer(l, "yaml", `server:
+  tls:
+    enabled: true
+    port: 8443`)
+
+		assert.True(t, c.GetBool("server.tls.enabled"))
+		assert.Equal(t, 8443, c.GetInt("server.tls.port"))
+	})
+
+	t.Run("with multiple strings", func(t *testing.T) {
+		t.Parallel()
+		c := config.NewStringsContainer(l, "yaml", firstMockFilesYaml, secondMockFilesYaml)
+
+		assert.Equal(t, "value2", c.GetString("yaml.key"))
+		assert.True(t, c.GetBool("yaml.bool"))
+	})
+
+	t.Run("with json", func(t *testing.T) {
+		t.Parallel()
+		c := config.NewStringContainer(l, "json", `{"server": {"name": "api"}}`)
+
+		assert.Equal(t, "api", c.GetString("server.name"))
+	})
+}
+
+func TestNewContainer_EmptySources(t *testing.T) {
+	t.Parallel()
+	logger := log.New(io.Discard)
+
+	t.Run("with all empty readers", func(t *testing.T) {
+		t.Parallel()
+		c := config.NewReaderContainer(logger, "yaml", strings.NewReader(""), strings.NewReader("  \n"))
+		assert.False(t, c.HasLoadErrors())
+
+		c.GetViper().SetDefault("defaulted.key", "default")
+		c.Set("set.key", "set")
+		assert.Equal(t, "default", c.GetString("defaulted.key"))
+		assert.Equal(t, "set", c.GetString("set.key"))
+	})
+
+	t.Run("with a mix of empty and populated readers", func(t *testing.T) {
+		t.Parallel()
+		c := config.NewReaderContainer(logger, "yaml",
+			strings.NewReader(""),
+			strings.NewReader(firstMockFilesYaml),
+			strings.NewReader(""),
+		)
+		assert.Equal(t, "value", c.GetString("yaml.key"))
+		assert.True(t, c.GetBool("yaml.bool"))
+	})
+
+	t.Run("with a mix of empty and populated files", func(t *testing.T) {
+		t.Parallel()
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, "empty.yml", []byte(""), 0o644))
+		require.NoError(t, afero.WriteFile(fs, "first.yml", []byte(firstMockFilesYaml), 0o644))
+		require.NoError(t, afero.WriteFile(fs, "blank.yml", []byte("\n\n"), 0o644))
+
+		c := config.NewFilesContainer(logger, fs, "empty.yml", "first.yml", "blank.yml")
+		assert.False(t, c.HasLoadErrors())
+		assert.Equal(t, "value", c.GetString("yaml.key"))
+		assert.Equal(t, 1, c.GetInt("yaml.int"))
+	})
 }
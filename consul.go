@@ -0,0 +1,82 @@
+package config
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/charmbracelet/log"
+)
+
+// ConsulOptions connection and parsing options for LoadConsulWithOptions.
+type ConsulOptions struct {
+	// Token ACL token sent with each request.
+	Token string
+	// Datacenter datacenter to query, defaulting to the agent's own.
+	Datacenter string
+	// Format config format of the stored value, inferred from the key's extension when empty and
+	// otherwise defaulting to yaml.
+	Format string
+	// Client HTTP client used for requests, e.g. to configure TLS. Defaults to a client with a sane timeout.
+	Client *http.Client
+}
+
+// consulProvider RemoteProvider reading raw values from the Consul KV HTTP API.
+type consulProvider struct {
+	addr string
+	opts ConsulOptions
+}
+
+// NewConsulProvider return a RemoteProvider reading keys from the Consul agent at addr, e.g.
+// "http://127.0.0.1:8500".
+func NewConsulProvider(addr string, opts ConsulOptions) RemoteProvider {
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: defaultHTTPTimeout}
+	}
+
+	return &consulProvider{addr: strings.TrimSuffix(addr, "/"), opts: opts}
+}
+
+// Get read the raw value stored at key.
+func (p *consulProvider) Get(ctx context.Context, key string) ([]byte, error) {
+	q := url.Values{"raw": {""}}
+	if p.opts.Datacenter != "" {
+		q.Set("dc", p.opts.Datacenter)
+	}
+
+	header := http.Header{}
+	if p.opts.Token != "" {
+		header.Set("X-Consul-Token", p.opts.Token)
+	}
+
+	u := p.addr + "/v1/kv/" + strings.TrimPrefix(key, "/") + "?" + q.Encode()
+
+	return fetchHTTP(ctx, p.opts.Client, u, header)
+}
+
+// LoadConsul Initialise a configuration container from the value stored at prefix in Consul KV. ctx bounds
+// the request.
+func LoadConsul(ctx context.Context, addr, prefix string, logger *log.Logger) (Containable, error) {
+	return LoadConsulWithOptions(ctx, addr, prefix, logger, ConsulOptions{})
+}
+
+// LoadConsulWithOptions Initialise a configuration container from the value stored at prefix in Consul KV
+// as LoadConsul, with connection and auth controlled by opts.
+func LoadConsulWithOptions(ctx context.Context, addr, prefix string, logger *log.Logger, opts ConsulOptions) (Containable, error) {
+	format := opts.Format
+	if format == "" {
+		format = remoteFormat(prefix)
+	}
+
+	return LoadRemote(ctx, NewConsulProvider(addr, opts), prefix, format, logger)
+}
+
+// remoteFormat infer the format of a remote key from its extension, defaulting to yaml.
+func remoteFormat(key string) string {
+	if format, err := formatFromPath(key); err == nil {
+		return format
+	}
+
+	return "yaml"
+}
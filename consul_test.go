@@ -0,0 +1,87 @@
+package config_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/charmbracelet/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/phpboyscout/config"
+)
+
+func newConsulServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, raw := r.URL.Query()["raw"]
+		if !raw || r.Header.Get("X-Consul-Token") != "secret" {
+			w.WriteHeader(http.StatusForbidden)
+
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v1/kv/app/config.yml":
+			_, _ = io.WriteString(w, firstMockFilesYaml)
+		case "/v1/kv/app/config.json":
+			_, _ = io.WriteString(w, `{"json": {"dc": "`+r.URL.Query().Get("dc")+`"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestLoadConsul(t *testing.T) {
+	t.Parallel()
+	logger := log.New(io.Discard)
+	srv := newConsulServer(t)
+
+	t.Run("with token", func(t *testing.T) {
+		t.Parallel()
+		c, err := config.LoadConsulWithOptions(context.Background(), srv.URL, "app/config.yml", logger, config.ConsulOptions{Token: "secret"})
+		require.NoError(t, err)
+		assert.Equal(t, "value", c.GetString("yaml.key"))
+	})
+
+	t.Run("with datacenter and inferred format", func(t *testing.T) {
+		t.Parallel()
+		c, err := config.LoadConsulWithOptions(context.Background(), srv.URL, "app/config.json", logger, config.ConsulOptions{
+			Token:      "secret",
+			Datacenter: "eu-west",
+			Client:     srv.Client(),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "eu-west", c.GetString("json.dc"))
+	})
+
+	t.Run("without token", func(t *testing.T) {
+		t.Parallel()
+		_, err := config.LoadConsul(context.Background(), srv.URL, "app/config.yml", logger)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "403")
+	})
+
+	t.Run("with missing key", func(t *testing.T) {
+		t.Parallel()
+		_, err := config.LoadConsulWithOptions(context.Background(), srv.URL, "app/missing.yml", logger, config.ConsulOptions{Token: "secret"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "404")
+	})
+
+	t.Run("with a cancelled context", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := config.LoadConsulWithOptions(ctx, srv.URL, "app/config.yml", logger, config.ConsulOptions{Token: "secret"})
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -4,141 +4,863 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/charmbracelet/log"
 	"github.com/fsnotify/fsnotify"
 	"github.com/go-errors/errors"
+	"github.com/spf13/afero"
+	"github.com/spf13/cast"
 	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
 )
 
 type Containable interface {
 	Get(key string) interface{}
 	GetBool(key string) bool
 	GetInt(key string) int
+	GetInt64(key string) int64
+	GetInt32(key string) int32
+	GetUint(key string) uint
+	GetUint64(key string) uint64
 	GetFloat(key string) float64
 	GetString(key string) string
+	GetStringMapStringSlice(key string) map[string][]string
 	GetTime(key string) time.Time
 	GetDuration(key string) time.Duration
 	GetViper() *viper.Viper
 	Has(key string) bool
+	IsSet(key string) bool
 	Sub(key string) Containable
 	AddObserver(o Observable)
-	AddObserverFunc(f func(Containable, chan error))
+	AddObserverFunc(f func(Containable, chan error)) func()
 	ToJSON() string
 	Dump()
 }
 
 // Container container for configuration.
 type Container struct {
-	ID        string
-	viper     *viper.Viper
-	logger    *log.Logger
-	observers []Observable
+	ID          string
+	viper       *viper.Viper
+	logger      *log.Logger
+	observers   []Observable
+	fs          afero.Fs
+	files       []string
+	loadedFiles []string
+	baseDir     string
+	format      string
+	raw         map[string]interface{}
+
+	loadErrors []error
+
+	finalizers          []func(Containable) error
+	subtreeObservers    []*subtreeObserver
+	eventObservers      []func(Containable, fsnotify.Event)
+	reloadHook          ReloadHook
+	reloadValidator     func(Containable) error
+	decryptor           func(ciphertext string) (string, error)
+	mu                  sync.RWMutex
+	reloadMu            sync.Mutex
+	reloadTimer         *time.Timer
+	watcher             *fsnotify.Watcher
+	symlinkTargets      map[string]string
+	secretKeys          []string
+	readLogging         bool
+	overrides           map[string]interface{}
+	stdFlags            map[string]interface{}
+	flagChanged         map[string]func() bool
+	envPrefix           string
+	envBindings         map[string][]string
+	deprecations        []deprecation
+	keyNormalizer       func(string) string
+	reloadables         []func()
+	changeSubs          []chan ConfigChange
+	sequentialObservers bool
+	frozen              atomic.Bool
+	freezePanics        atomic.Bool
 }
 
 // Get interface value from config.
 func (c *Container) Get(key string) interface{} {
-	return c.viper.Get(key)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return logged(c, key, c.decryptValue(key, c.viper.Get(c.lookupKey(key))))
 }
 
 // GetBool get Bool value from config.
 func (c *Container) GetBool(key string) bool {
-	return c.viper.GetBool(key)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return logged(c, key, c.viper.GetBool(c.lookupKey(key)))
 }
 
 // GetInt get Bool value from config.
 func (c *Container) GetInt(key string) int {
-	return c.viper.GetInt(key)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return logged(c, key, c.viper.GetInt(c.lookupKey(key)))
+}
+
+// GetInt64 get int64 value from config. Values that don't fit are logged and read as zero; use
+// GetInt64Strict to handle them.
+func (c *Container) GetInt64(key string) int64 {
+	return sized(c, key, c.GetInt64Strict)
+}
+
+// GetInt32 get int32 value from config. Values outside the range of int32 are logged and read as zero
+// rather than truncated; use GetInt32Strict to handle them.
+func (c *Container) GetInt32(key string) int32 {
+	return sized(c, key, c.GetInt32Strict)
+}
+
+// GetUint get uint value from config. Negative values and values that don't fit are logged and read as
+// zero; use GetUintStrict to handle them.
+func (c *Container) GetUint(key string) uint {
+	return sized(c, key, c.GetUintStrict)
+}
+
+// GetUint64 get uint64 value from config. Negative values are logged and read as zero; use
+// GetUint64Strict to handle them.
+func (c *Container) GetUint64(key string) uint64 {
+	return sized(c, key, c.GetUint64Strict)
+}
+
+// GetFloat32 get float32 value from config. Values outside the range of float32 are logged and read as
+// zero; use GetFloat32Strict to handle them.
+func (c *Container) GetFloat32(key string) float32 {
+	return sized(c, key, c.GetFloat32Strict)
+}
+
+// sized read key with a strict sized getter, logging any value that can't be represented and returning
+// zero for it. Missing keys read as zero silently, as with the other getters.
+func sized[T any](c *Container, key string, get func(string) (T, error)) T {
+	v, err := get(key)
+	if err != nil && !errors.Is(err, ErrKeyNotSet) {
+		c.logger.Warn("unable to read config value", "key", key, "error", err)
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return logged(c, key, v)
 }
 
 // GetFloat get Float value from config.
 func (c *Container) GetFloat(key string) float64 {
-	return c.viper.GetFloat64(key)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return logged(c, key, c.viper.GetFloat64(c.lookupKey(key)))
 }
 
 // GetString get string value from config.
 func (c *Container) GetString(key string) string {
-	return c.viper.GetString(key)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return logged(c, key, c.decryptOrEmpty(key, c.viper.GetString(c.lookupKey(key))))
+}
+
+// GetStringSlice get string slice value from config.
+func (c *Container) GetStringSlice(key string) []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return logged(c, key, c.decryptSlice(key, c.viper.GetStringSlice(c.lookupKey(key))))
+}
+
+// GetStringSliceUnique get string slice value from config with duplicate entries removed, preserving first occurrence order.
+func (c *Container) GetStringSliceUnique(key string) []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	values := c.decryptSlice(key, c.viper.GetStringSlice(c.lookupKey(key)))
+	seen := make(map[string]struct{}, len(values))
+	unique := make([]string, 0, len(values))
+
+	for _, v := range values {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		unique = append(unique, v)
+	}
+
+	return logged(c, key, unique)
+}
+
+// GetStringMapStringSlice get map of string slices from config. Values holding a single string are
+// returned as a one-element slice.
+func (c *Container) GetStringMapStringSlice(key string) map[string][]string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	raw := c.viper.GetStringMap(c.lookupKey(key))
+	m := make(map[string][]string, len(raw))
+
+	for k, v := range raw {
+		if s, ok := v.(string); ok {
+			m[k] = []string{c.decryptOrEmpty(key, s)}
+
+			continue
+		}
+		m[k] = c.decryptSlice(key, cast.ToStringSlice(v))
+	}
+
+	return logged(c, key, m)
 }
 
 // GetTime get time value from config.
 func (c *Container) GetTime(key string) time.Time {
-	return c.viper.GetTime(key)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return logged(c, key, c.viper.GetTime(c.lookupKey(key)))
 }
 
 // GetDuration get duration value from config.
 func (c *Container) GetDuration(key string) time.Duration {
-	return c.viper.GetDuration(key)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return logged(c, key, c.viper.GetDuration(c.lookupKey(key)))
 }
 
-// GetViper retrieves the underlying Viper configuration.
+// GetViper retrieves the underlying Viper configuration. Viper is not safe for concurrent use, so the returned
+// instance must not be used while a watched or polled container may be reloading.
 func (c *Container) GetViper() *viper.Viper {
 	return c.viper
 }
 
-// Has retrieves the underlying Viper configuration.
+// Has reports whether key is present in the loaded config files. Values provided only by environment
+// variables, defaults, flags or Set are not considered; use IsSet for that.
 func (c *Container) Has(key string) bool {
-	return c.viper.InConfig(key)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.viper.InConfig(c.lookupKey(key))
+}
+
+// IsSet reports whether key has a value from any source: config files, environment variables, flags,
+// defaults or Set.
+func (c *Container) IsSet(key string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.viper.IsSet(c.lookupKey(key))
+}
+
+// Set override the value for key in the container.
+func (c *Container) Set(key string, value interface{}) {
+	// deferred ahead of the unlock so that it runs once the lock is released.
+	defer c.refreshReloadables()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.ignoreFrozen("Set " + key) {
+		return
+	}
+
+	k := strings.ToLower(c.canonicalKey(key))
+	if c.overrides == nil {
+		c.overrides = make(map[string]interface{})
+	}
+	for o := range c.overrides {
+		if strings.HasPrefix(o, k+".") {
+			delete(c.overrides, o)
+		}
+	}
+	c.overrides[k] = value
+	c.viper.Set(k, value)
+}
+
+// Unset remove a value previously applied with Set, along with any overrides nested beneath key, so that
+// key resolves from flags, environment, config files or defaults again.
+func (c *Container) Unset(key string) error {
+	defer c.refreshReloadables()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.frozenErr("Unset " + key); err != nil {
+		return err
+	}
+
+	k := strings.ToLower(c.canonicalKey(key))
+	found := false
+	for o := range c.overrides {
+		if o == k || strings.HasPrefix(o, k+".") {
+			delete(c.overrides, o)
+			found = true
+		}
+	}
+
+	if !found {
+		return errors.Errorf("%w: %s has no override to unset", ErrKeyNotSet, key)
+	}
+
+	c.rebuildOverrides(strings.Split(k, ".")[0])
+
+	return nil
 }
 
-// Sub returns a subtree of the parent configuration.
+// rebuildOverrides clear the override layer beneath each of roots and re-apply the standard library flags
+// and overrides that remain there. Viper has no delete, but a nil override at the top level is treated as
+// absent and falls through to the lower layers, whereas a nil further down would hide its siblings from
+// reads of the parent key.
+func (c *Container) rebuildOverrides(roots ...string) {
+	for _, root := range roots {
+		c.viper.Set(root, nil)
+	}
+
+	// flags first so that values applied with Set still take precedence over them.
+	for _, layer := range []map[string]interface{}{c.stdFlags, c.overrides} {
+		keys := make([]string, 0, len(layer))
+		for k := range layer {
+			for _, root := range roots {
+				if k == root || strings.HasPrefix(k, root+".") {
+					keys = append(keys, k)
+
+					break
+				}
+			}
+		}
+
+		// parents first, so that nested overrides are applied within them rather than being replaced.
+		sort.Slice(keys, func(i, j int) bool {
+			return strings.Count(keys[i], ".") < strings.Count(keys[j], ".")
+		})
+		for _, k := range keys {
+			c.viper.Set(k, layer[k])
+		}
+	}
+}
+
+// Reset discard every value applied with Set and re-read the loaded config files, returning the container
+// to the state of its original sources. Defaults, environment and flag bindings and registered observers
+// are kept, and observers are notified. Containers built from readers only have their overrides discarded,
+// as the readers can't be read again.
+func (c *Container) Reset() error {
+	defer c.refreshReloadables()
+
+	before := c.changeSnapshot()
+
+	c.mu.Lock()
+	if err := c.frozenErr("Reset"); err != nil {
+		c.mu.Unlock()
+
+		return err
+	}
+
+	roots := make([]string, 0, len(c.overrides))
+	for o := range c.overrides {
+		roots = append(roots, strings.Split(o, ".")[0])
+	}
+	c.overrides = nil
+	c.rebuildOverrides(roots...)
+	c.mu.Unlock()
+
+	if len(c.files) > 0 {
+		if err := c.reloadFiles(); err != nil {
+			return err
+		}
+	}
+
+	c.logger.Info("Config reset")
+	c.publishChange(c.files, before)
+	c.notifyObservers()
+
+	return nil
+}
+
+// SetFs change the filesystem used for subsequent reads, such as reloads. Config already loaded is left as is.
+func (c *Container) SetFs(fs afero.Fs) {
+	if c.ignoreFrozen("SetFs") {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.fs = fs
+	c.viper.SetFs(fs)
+}
+
+// Clone create an isolated copy of the container's current settings. Mutating the clone does not affect
+// the original, and the clone shares neither the original's observers nor its file watcher. The environment
+// prefix and bindings, deprecations and values applied with Set or BindStdFlags are carried over, so the
+// clone resolves, explains and unsets keys as the original does.
+func (c *Container) Clone() *Container {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// the settings are taken from beneath the override layer, which is replayed onto the clone, so that
+	// Unset on the clone falls through to the same values as it would on the original.
+	roots := make([]string, 0, len(c.overrides)+len(c.stdFlags))
+	for _, layer := range []map[string]interface{}{c.stdFlags, c.overrides} {
+		for k := range layer {
+			roots = append(roots, strings.Split(k, ".")[0])
+		}
+	}
+	for _, root := range roots {
+		c.viper.Set(root, nil)
+	}
+	settings := c.viper.AllSettings()
+	c.rebuildOverrides(roots...)
+
+	v := viper.New()
+	configureViper(v)
+	if c.envPrefix != "" {
+		v.SetEnvPrefix(c.envPrefix)
+	}
+	envBindings := make(map[string][]string, len(c.envBindings))
+	for key, names := range c.envBindings {
+		envBindings[key] = append([]string(nil), names...)
+		if err := v.BindEnv(append([]string{key}, names...)...); err != nil {
+			c.logger.Warn("unable to clone env binding", "key", key, "stacktrace", errors.Wrap(err, 0).ErrorStack())
+		}
+	}
+	if err := v.MergeConfigMap(settings); err != nil {
+		c.logger.Warn("unable to clone config", "stacktrace", errors.Wrap(err, 0).ErrorStack())
+	}
+
+	clone := &Container{
+		ID:        fmt.Sprintf("%s@clone", c.ID),
+		viper:     v,
+		logger:    c.logger,
+		observers: make([]Observable, 0),
+		baseDir:   c.baseDir,
+
+		secretKeys:    append([]string(nil), c.secretKeys...),
+		decryptor:     c.decryptor,
+		readLogging:   c.readLogging,
+		keyNormalizer: c.keyNormalizer,
+		envPrefix:     c.envPrefix,
+		envBindings:   envBindings,
+		deprecations:  append([]deprecation(nil), c.deprecations...),
+		overrides:     copyLayer(c.overrides),
+		stdFlags:      copyLayer(c.stdFlags),
+		flagChanged:   make(map[string]func() bool, len(c.flagChanged)),
+	}
+	for k, changed := range c.flagChanged {
+		clone.flagChanged[k] = changed
+	}
+	clone.rebuildOverrides(roots...)
+
+	return clone
+}
+
+// copyLayer deep copy an override layer, so that values applied to a clone aren't shared with the original.
+func copyLayer(layer map[string]interface{}) map[string]interface{} {
+	if layer == nil {
+		return nil
+	}
+
+	out := make(map[string]interface{}, len(layer))
+	for k, v := range layer {
+		out[k] = copyRaw(v)
+	}
+
+	return out
+}
+
+// Sub returns a subtree of the parent configuration, taken from the fully merged settings of every source.
+// When the subtree is absent an empty, but fully usable, container is returned.
 func (c *Container) Sub(key string) Containable {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	v := viper.New()
+	if subtree := subtreeSettings(c.viper.AllSettings(), c.lookupKey(key)); subtree != nil {
+		if err := v.MergeConfigMap(subtree); err != nil {
+			c.logger.Warn("unable to build config subtree", "key", key, "stacktrace", errors.Wrap(err, 0).ErrorStack())
+		}
+	}
+
 	return &Container{
 		ID:        fmt.Sprintf("%s#%s", c.ID, key),
-		viper:     c.viper.Sub(key),
+		viper:     v,
 		logger:    c.logger,
 		observers: make([]Observable, 0),
+		baseDir:   c.baseDir,
+
+		raw:           rawSubtree(c.raw, c.lookupKey(key)),
+		secretKeys:    c.subSecretKeys(c.lookupKey(key)),
+		decryptor:     c.decryptor,
+		readLogging:   c.readLogging,
+		keyNormalizer: c.keyNormalizer,
+	}
+}
+
+// subtreeSettings descend settings along the dotted key, returning the map found there or nil if the key is
+// absent or does not hold a map. Numeric segments index into lists.
+func subtreeSettings(settings map[string]interface{}, key string) map[string]interface{} {
+	var cur interface{} = settings
+
+	for _, segment := range strings.Split(strings.ToLower(key), ".") {
+		switch t := cur.(type) {
+		case map[string]interface{}:
+			cur = t[segment]
+		case []interface{}:
+			i, err := strconv.Atoi(segment)
+			if err != nil || i < 0 || i >= len(t) {
+				return nil
+			}
+			cur = t[i]
+		default:
+			return nil
+		}
+	}
+
+	m, err := cast.ToStringMapE(cur)
+	if err != nil || cur == nil {
+		return nil
+	}
+
+	return m
+}
+
+// GetSlice get a list of objects from config, each element wrapped as its own container so that nested
+// fields can be read with the usual getters. Elements that are not objects are skipped.
+func (c *Container) GetSlice(key string) []Containable {
+	items, _ := c.getSlice(key, false)
+
+	return items
+}
+
+// GetSliceStrict behave like GetSlice but return an error if any element is not an object.
+func (c *Container) GetSliceStrict(key string) ([]Containable, error) {
+	return c.getSlice(key, true)
+}
+
+func (c *Container) getSlice(key string, strict bool) ([]Containable, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	raw := cast.ToSlice(c.viper.Get(c.lookupKey(key)))
+	items := make([]Containable, 0, len(raw))
+
+	for i, e := range raw {
+		m, err := cast.ToStringMapE(e)
+		if err != nil {
+			if strict {
+				return nil, errors.Errorf("config key %s[%d] is not an object", key, i)
+			}
+
+			continue
+		}
+
+		v := viper.New()
+		if err := v.MergeConfigMap(m); err != nil {
+			return nil, errors.Wrap(err, 0)
+		}
+
+		items = append(items, &Container{
+			ID:        fmt.Sprintf("%s#%s[%d]", c.ID, key, i),
+			viper:     v,
+			logger:    c.logger,
+			observers: make([]Observable, 0),
+			baseDir:   c.baseDir,
+
+			secretKeys:    c.subSecretKeys(c.lookupKey(key)),
+			decryptor:     c.decryptor,
+			readLogging:   c.readLogging,
+			keyNormalizer: c.keyNormalizer,
+		})
+	}
+
+	return items, nil
+}
+
+// SubPath descend one dotted segment at a time, returning an error at the first segment that is missing
+// or does not hold a subtree.
+func (c *Container) SubPath(path string) (Containable, error) {
+	segments := strings.Split(path, ".")
+	cur := c
+
+	for i, segment := range segments {
+		descended := strings.Join(segments[:i+1], ".")
+
+		cur.mu.RLock()
+		value := cur.viper.Get(segment)
+		cur.mu.RUnlock()
+
+		if value == nil {
+			return nil, errors.Errorf("%w: %s (missing segment %q)", ErrKeyNotSet, path, descended)
+		}
+
+		if _, err := cast.ToStringMapE(value); err != nil {
+			return nil, errors.Errorf("config key %q is not a subtree", descended)
+		}
+
+		cur = cur.Sub(segment).(*Container)
 	}
+
+	return cur, nil
+}
+
+// indexPattern matches bracketed array indexes such as the "[1]" in "servers[1].host".
+var indexPattern = regexp.MustCompile(`\[(\d+)\]`)
+
+// indexedKey translate bracketed array index syntax into viper's dotted form, e.g. "features[0]" to "features.0".
+func indexedKey(key string) string {
+	if !strings.Contains(key, "[") {
+		return key
+	}
+
+	return indexPattern.ReplaceAllString(key, ".$1")
+}
+
+// WithReadLogging enable debug logging of every key read through the container's getters.
+func (c *Container) WithReadLogging() *Container {
+	c.mu.Lock()
+	c.readLogging = true
+	c.mu.Unlock()
+
+	return c
 }
 
-func (c *Container) handleReadFileError(err error) {
+// logged log the resolved value of a read when read logging is enabled, passing the value through. c.mu must
+// be held.
+func logged[T any](c *Container, key string, value T) T {
+	if c.readLogging {
+		var logValue interface{} = redactedValue
+		if !c.isSecret(key) {
+			// parent keys are redacted recursively, so that secrets nested beneath them aren't logged.
+			logValue = c.redactValue(value, strings.ToLower(key))
+		}
+		c.logger.Debug("config read", "key", key, "value", logValue)
+	}
+
+	return value
+}
+
+func (c *Container) handleReadFileError(source string, err error) {
+	if err == nil {
+		return
+	}
+
 	// just use the default value(s) if the config file was not found.
 	var pathError *os.PathError
+	var parseError viper.ConfigParseError
+	var formatError formatParseError
 	if errors.As(err, &pathError) {
-		c.logger.Warn("could not load config file. Using default values", "stacktrace", errors.Wrap(err, 0).ErrorStack())
-	} else if err != nil { // Handle other errors that occurred while reading the config file
-		c.logger.Warn(fmt.Sprintf("Could not read the config file (%s)", err), "stacktrace", errors.Wrap(err, 0).ErrorStack())
+		c.logger.Warn("could not load config file. Using default values", "file", source, "stacktrace", errors.Wrap(err, 0).ErrorStack())
+
+		return
+	} else if errors.As(err, &parseError) || errors.As(err, &formatError) {
+		c.logger.Error(fmt.Sprintf("Could not parse the config file %s (%s)", source, err), "stacktrace", errors.Wrap(err, 0).ErrorStack())
+	} else { // Handle other errors that occurred while reading the config file
+		c.logger.Warn(fmt.Sprintf("Could not read the config file (%s)", err), "file", source, "stacktrace", errors.Wrap(err, 0).ErrorStack())
 	}
+
+	c.loadErrors = append(c.loadErrors, errors.Errorf("%s: %w", source, err))
 }
 
-// watchConfig monitor the changes in the config file.
-func (c *Container) watchConfig() {
-	c.viper.OnConfigChange(func(e fsnotify.Event) {
-		c.logger.Infof("Config updated %v", e)
-		errs := make(chan error)
-		wg := &sync.WaitGroup{}
-		for _, o := range c.observers {
-			wg.Add(1)
-			go func(o Observable, wg *sync.WaitGroup, errs chan error) {
-				o.Run(c, errs)
-				wg.Done()
-			}(o, wg, errs)
+// HasLoadErrors reports whether any config source failed to read or parse during construction.
+// Missing files are not considered load errors.
+func (c *Container) HasLoadErrors() bool {
+	return len(c.loadErrors) > 0
+}
+
+// LoadErrors retrieve the errors recorded while reading or parsing config sources during construction.
+func (c *Container) LoadErrors() []error {
+	return c.loadErrors
+}
+
+// notifyObservers run all attached observers concurrently, waiting for them to complete, followed by any finalizers.
+func (c *Container) notifyObservers() {
+	errs, done := c.observerErrors()
+	defer done()
+
+	wg := &sync.WaitGroup{}
+	c.mu.RLock()
+	observers := c.observers
+	sequential := c.sequentialObservers
+	c.mu.RUnlock()
+
+	for _, o := range observers {
+		if sequential {
+			c.runObserver(o, errs)
+
+			continue
 		}
-		wg.Wait()
-	})
-	c.viper.WatchConfig()
+
+		wg.Add(1)
+		go func(o Observable, wg *sync.WaitGroup, errs chan error) {
+			defer wg.Done()
+			c.runObserver(o, errs)
+		}(o, wg, errs)
+	}
+	wg.Wait()
+
+	c.notifySubtreeObservers()
+
+	if err := c.runFinalizers(); err != nil {
+		c.logger.Error("config finalizers failed", "error", err)
+	}
+}
+
+// observerErrors return a channel for observers to report errors on, each of which is logged and passed to
+// the reload hook's OnReloadError. Call done once the observers have returned to stop draining it; observers
+// must not keep the channel beyond their run.
+func (c *Container) observerErrors() (errs chan error, done func()) {
+	errs = make(chan error)
+	drained := make(chan struct{})
+
+	go func() {
+		defer close(drained)
+		for err := range errs {
+			c.logger.Error("config observer failed", "error", err)
+			if hook := c.currentReloadHook(); hook != nil {
+				hook.OnReloadError(err)
+			}
+		}
+	}()
+
+	return errs, func() {
+		close(errs)
+		<-drained
+	}
+}
+
+// runObserver run o, recovering any panic so that a faulty observer can't take the process down on reload.
+func (c *Container) runObserver(o Observable, errs chan error) {
+	c.guard("observer", func() { o.Run(c, errs) })
+}
+
+// guard run the user callback f, recovering any panic so that a faulty callback can't take the process down
+// on reload. The panic is logged with its stack and passed to the reload hook's OnReloadError.
+func (c *Container) guard(callback string, f func()) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+
+		err := errors.Wrap(r, 2)
+		c.logger.Error("config "+callback+" panicked", "error", err, "stacktrace", err.ErrorStack())
+		if hook := c.currentReloadHook(); hook != nil {
+			hook.OnReloadError(err)
+		}
+	}()
+
+	f()
+}
+
+// runFinalizers run finalizers in reverse registration order, aggregating any errors. A panicking
+// finalizer is recovered and logged, and the rest still run.
+func (c *Container) runFinalizers() error {
+	c.mu.RLock()
+	finalizers := c.finalizers
+	c.mu.RUnlock()
+
+	var errs []error
+	for i := len(finalizers) - 1; i >= 0; i-- {
+		finalizer := finalizers[i]
+		c.guard("finalizer", func() {
+			if err := finalizer(c); err != nil {
+				errs = append(errs, err)
+			}
+		})
+	}
+
+	return errors.Join(errs...)
 }
 
 // AddObserver attach observer to trigger on config update.
 func (c *Container) AddObserver(o Observable) {
+	if c.ignoreFrozen("AddObserver") {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	c.observers = append(c.observers, o)
 }
 
-// AddObserverFunc attach function to trigger on config update.
-func (c *Container) AddObserverFunc(f func(Containable, chan error)) {
-	c.observers = append(c.observers, Observer{f})
+// AddObserverWithInitial attach observer to trigger on config update, as AddObserver, and run it once
+// straight away with the current config, so the same code applies config at startup and on reload.
+func (c *Container) AddObserverWithInitial(o Observable) {
+	if c.ignoreFrozen("AddObserverWithInitial") {
+		return
+	}
+
+	c.AddObserver(o)
+
+	errs, done := c.observerErrors()
+	defer done()
+	c.runObserver(o, errs)
+}
+
+// AddObserverFunc attach function to trigger on config update, returning a func that detaches it again.
+func (c *Container) AddObserverFunc(f func(Containable, chan error)) func() {
+	if c.ignoreFrozen("AddObserverFunc") {
+		return func() {}
+	}
+
+	o := &Observer{f}
+	c.mu.Lock()
+	c.observers = append(c.observers, o)
+	c.mu.Unlock()
+
+	return func() {
+		c.removeObserver(o)
+	}
+}
+
+// removeObserver detach the given observer if it is attached.
+func (c *Container) removeObserver(o Observable) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, existing := range c.observers {
+		if existing == o {
+			c.observers = append(c.observers[:i:i], c.observers[i+1:]...)
+
+			return
+		}
+	}
+}
+
+// AddFinalizer attach function to run after observers on config update.
+// Finalizers run sequentially in reverse registration order, so later setup is torn down first.
+func (c *Container) AddFinalizer(f func(Containable) error) {
+	if c.ignoreFrozen("AddFinalizer") {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.finalizers = append(c.finalizers, f)
 }
 
 // GetObservers retrieve all currently attached Observers.
 func (c *Container) GetObservers() []Observable {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
 	return c.observers
 }
 
-// Dump return config as json string.
+// ToJSON return config as json string with secret values redacted.
 func (c *Container) ToJSON() string {
-	s := c.viper.AllSettings()
+	c.mu.RLock()
+	s := c.redactedSettings()
+	c.mu.RUnlock()
+
 	bs, err := json.Marshal(s)
 	if err != nil {
 		c.logger.Fatal("unable to marshal config to YAML", "stacktrace", errors.Wrap(err, 0).ErrorStack())
@@ -147,6 +869,92 @@ func (c *Container) ToJSON() string {
 	return string(bs)
 }
 
+// ToYAML return config as YAML string with secret values redacted.
+func (c *Container) ToYAML() string {
+	c.mu.RLock()
+	settings := c.redactedSettings()
+	c.mu.RUnlock()
+
+	bs, err := yaml.Marshal(settings)
+	if err != nil {
+		c.logger.Fatal("unable to marshal config to YAML", "stacktrace", errors.Wrap(err, 0).ErrorStack())
+	}
+
+	return string(bs)
+}
+
+// CanonicalYAML return config as deterministic YAML with map keys sorted recursively, suitable for golden tests.
+func (c *Container) CanonicalYAML() string {
+	c.mu.RLock()
+	settings := c.viper.AllSettings()
+	c.mu.RUnlock()
+
+	bs, err := yaml.Marshal(canonicalNode(settings))
+	if err != nil {
+		c.logger.Fatal("unable to marshal config to YAML", "stacktrace", errors.Wrap(err, 0).ErrorStack())
+	}
+
+	return string(bs)
+}
+
+// canonicalNode build a yaml.Node tree from v with all mapping keys in sorted order.
+func canonicalNode(v interface{}) *yaml.Node {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		n := &yaml.Node{Kind: yaml.MappingNode}
+		for _, k := range keys {
+			n.Content = append(n.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: k}, canonicalNode(t[k]))
+		}
+
+		return n
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, val := range t {
+			m[fmt.Sprint(k)] = val
+		}
+
+		return canonicalNode(m)
+	case []interface{}:
+		n := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, val := range t {
+			n.Content = append(n.Content, canonicalNode(val))
+		}
+
+		return n
+	default:
+		n := &yaml.Node{}
+		if err := n.Encode(t); err != nil {
+			return &yaml.Node{Kind: yaml.ScalarNode, Value: fmt.Sprint(t)}
+		}
+
+		return n
+	}
+}
+
 func (c *Container) Dump() {
 	fmt.Println(c.ToJSON())
 }
+
+// LogEffectiveConfig log the full effective config through the container's logger at level, with secret
+// values redacted as in ToJSON. Useful as an opt-in startup self-test when troubleshooting deployments.
+// FatalLevel is logged as an error rather than exiting.
+func (c *Container) LogEffectiveConfig(level log.Level) {
+	msg, keyvals := "Effective config", []interface{}{"config", c.ToJSON()}
+
+	switch level {
+	case log.DebugLevel:
+		c.logger.Debug(msg, keyvals...)
+	case log.WarnLevel:
+		c.logger.Warn(msg, keyvals...)
+	case log.ErrorLevel, log.FatalLevel:
+		c.logger.Error(msg, keyvals...)
+	default:
+		c.logger.Info(msg, keyvals...)
+	}
+}
@@ -1,13 +1,18 @@
 package config_test
 
 import (
+	"bytes"
+	"context"
 	"io"
 	"os"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/charmbracelet/log"
+	"github.com/go-errors/errors"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -42,10 +47,10 @@ func TestContainer_AddObserver(t *testing.T) {
 
 		c := config.NewFilesContainer(logger, afero.NewOsFs(), filename)
 		origValue := c.GetString("yaml.key")
-		observed := 0
+		var observed int32
 
 		observeFunc := func(c config.Containable, errors chan error) {
-			observed++
+			atomic.AddInt32(&observed, 1)
 			newValue := c.GetString("yaml.key")
 			// t.Logf("observed = %d, origValue = %s, newValue = %s", observed, origValue, newValue)
 			if origValue == newValue {
@@ -67,7 +72,7 @@ func TestContainer_AddObserver(t *testing.T) {
 
 		assert.Len(t, c.GetObservers(), 2)
 
-		if observed >= 2 && observed%len(c.GetObservers()) != 0 {
+		if observed := int(atomic.LoadInt32(&observed)); observed >= 2 && observed%len(c.GetObservers()) != 0 {
 			// fsnotify can at times trigger multiple times, so the test accounts for this by testing
 			// for the modulus of observations to the number of observers
 			t.Errorf("Expected 2 observations, Observed: %d", observed)
@@ -75,6 +80,141 @@ func TestContainer_AddObserver(t *testing.T) {
 	})
 }
 
+func TestContainer_AddObserverWithInitial(t *testing.T) {
+	t.Parallel()
+	l := log.New(io.Discard)
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "config.yml", []byte(firstMockFilesYaml), 0o644))
+
+	c := config.NewFilesContainer(l, fs, "config.yml")
+	t.Cleanup(func() { _ = c.Close() })
+
+	var mu sync.Mutex
+	seen := make([]string, 0)
+	c.AddObserverWithInitial(observerFunc(func(cfg config.Containable) {
+		mu.Lock()
+		defer mu.Unlock()
+		seen = append(seen, cfg.GetString("yaml.key"))
+	}))
+
+	mu.Lock()
+	assert.Equal(t, []string{"value"}, seen)
+	mu.Unlock()
+
+	require.NoError(t, afero.WriteFile(fs, "config.yml", []byte(secondMockFilesYaml), 0o644))
+	require.NoError(t, c.Reset())
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"value", "value2"}, seen)
+}
+
+func TestContainer_AddObserverWithInitial_Error(t *testing.T) {
+	t.Parallel()
+	l := log.New(io.Discard)
+	c := config.NewReaderContainer(l, "yaml", strings.NewReader(firstMockFilesYaml))
+	hook := &fakeReloadHook{}
+	c.SetReloadHook(hook)
+
+	registered := make(chan struct{})
+	go func() {
+		defer close(registered)
+		c.AddObserverWithInitial(TestObserver{func(_ config.Containable, errs chan error) {
+			errs <- errors.New("unable to apply config")
+		}})
+	}()
+
+	select {
+	case <-registered:
+	case <-time.After(time.Second):
+		t.Fatal("AddObserverWithInitial blocked on the observer's error")
+	}
+
+	_, errCount := hook.counts()
+	assert.Equal(t, 1, errCount)
+}
+
+// observerFunc adapt a function to Observable.
+type observerFunc func(config.Containable)
+
+func (f observerFunc) Run(c config.Containable, _ chan error) {
+	f(c)
+}
+
+func TestContainer_AddObserverFunc_Unsubscribe(t *testing.T) {
+	t.Parallel()
+	logger := log.New(io.Discard)
+	c := config.NewReaderContainer(logger, "yaml", strings.NewReader(firstMockFilesYaml))
+
+	var first, second int32
+	unsubscribeFirst := c.AddObserverFunc(func(config.Containable, chan error) { atomic.AddInt32(&first, 1) })
+	c.AddObserverFunc(func(config.Containable, chan error) { atomic.AddInt32(&second, 1) })
+	require.Len(t, c.GetObservers(), 2)
+
+	unsubscribeFirst()
+	require.Len(t, c.GetObservers(), 1)
+
+	unsubscribeFirst()
+	require.Len(t, c.GetObservers(), 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c.StartPolling(ctx, 10*time.Millisecond, func() (config.Containable, error) {
+		return config.NewReaderContainer(logger, "yaml", strings.NewReader(secondMockFilesYaml)), nil
+	})
+
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&second) == 1 }, time.Second, 5*time.Millisecond)
+	assert.Zero(t, atomic.LoadInt32(&first))
+}
+
+func TestContainer_AddFinalizer(t *testing.T) {
+	t.Parallel()
+	logger := log.New(io.Discard)
+	c := config.NewReaderContainer(logger, "yaml", strings.NewReader(firstMockFilesYaml))
+
+	mu := sync.Mutex{}
+	calls := make([]string, 0)
+	record := func(name string) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls = append(calls, name)
+	}
+
+	c.AddObserverFunc(func(config.Containable, chan error) { record("observer") })
+	c.AddFinalizer(func(config.Containable) error {
+		record("first")
+
+		return nil
+	})
+	c.AddFinalizer(func(config.Containable) error {
+		record("second")
+
+		return errors.New("second failed")
+	})
+	c.AddFinalizer(func(config.Containable) error {
+		record("third")
+
+		return errors.New("third failed")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c.StartPolling(ctx, 10*time.Millisecond, func() (config.Containable, error) {
+		return config.NewReaderContainer(logger, "yaml", strings.NewReader(secondMockFilesYaml)), nil
+	})
+
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return len(calls) == 4
+	}, time.Second, 5*time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"observer", "third", "second", "first"}, calls)
+}
+
 func TestContainer_Get(t *testing.T) {
 	t.Parallel()
 	l := log.New(io.Discard)
@@ -139,6 +279,484 @@ func TestContainer_Sub(t *testing.T) {
 
 }
 
+func TestContainer_GetStringMapStringSlice(t *testing.T) {
+	t.Parallel()
+
+	l := log.New(io.Discard)
+	c := config.NewReaderContainer(l, "yaml", strings.NewReader(`routes:
+  api: [v1, v2]
+  web: [home, about]
+  admin: "admin dashboard"`))
+
+	assert.Equal(t, map[string][]string{
+		"api":   {"v1", "v2"},
+		"web":   {"home", "about"},
+		"admin": {"admin dashboard"},
+	}, c.GetStringMapStringSlice("routes"))
+
+	missing := c.GetStringMapStringSlice("missing")
+	assert.NotNil(t, missing)
+	assert.Empty(t, missing)
+}
+
+func TestContainer_IsSet(t *testing.T) {
+	t.Setenv("ENVONLY_KEY", "from-env")
+
+	l := log.New(io.Discard)
+	c := config.NewReaderContainer(l, "yaml", strings.NewReader(firstMockFilesYaml))
+
+	assert.True(t, c.IsSet("envonly.key"))
+	assert.False(t, c.Has("envonly.key"))
+
+	assert.True(t, c.IsSet("yaml.key"))
+	assert.True(t, c.Has("yaml.key"))
+
+	c.Set("override.key", "set")
+	assert.True(t, c.IsSet("override.key"))
+	assert.False(t, c.Has("override.key"))
+
+	assert.False(t, c.IsSet("missing.key"))
+}
+
+func TestContainer_Unset(t *testing.T) {
+	t.Parallel()
+
+	l := log.New(io.Discard)
+	c := config.NewReaderContainer(l, "yaml", strings.NewReader(firstMockFilesYaml))
+	c.GetViper().SetDefault("defaulted.key", "default")
+
+	t.Run("reverts to file value", func(t *testing.T) {
+		c.Set("yaml.key", "overridden")
+		require.Equal(t, "overridden", c.GetString("yaml.key"))
+
+		require.NoError(t, c.Unset("yaml.key"))
+		assert.Equal(t, "value", c.GetString("yaml.key"))
+		assert.True(t, c.GetBool("yaml.bool"))
+	})
+
+	t.Run("parent reads include file values", func(t *testing.T) {
+		c.Set("yaml.key", "overridden")
+		c.Set("yaml.extra", "added")
+		require.NoError(t, c.Unset("yaml.key"))
+		assert.Equal(t, "added", c.GetString("yaml.extra"))
+		assert.Equal(t, "value", c.GetString("yaml.key"))
+
+		require.NoError(t, c.Unset("yaml.extra"))
+		yaml, ok := c.Get("yaml").(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, "value", yaml["key"])
+		assert.Equal(t, true, yaml["bool"])
+		assert.ElementsMatch(t, []string{"key", "bool", "int", "float", "time", "duration"}, c.GetMapKeys("yaml"))
+	})
+
+	t.Run("reverts to default value", func(t *testing.T) {
+		c.Set("defaulted.key", "overridden")
+		require.NoError(t, c.Unset("defaulted.key"))
+		assert.Equal(t, "default", c.GetString("defaulted.key"))
+	})
+
+	t.Run("removes nested overrides", func(t *testing.T) {
+		c.Set("added.nested.key", "set")
+		c.Set("added.other", "set")
+		require.NoError(t, c.Unset("added"))
+		assert.False(t, c.IsSet("added.nested.key"))
+		assert.False(t, c.IsSet("added.other"))
+		assert.NotContains(t, c.GetViper().AllSettings(), "added")
+	})
+
+	t.Run("without override", func(t *testing.T) {
+		require.ErrorIs(t, c.Unset("yaml.key"), config.ErrKeyNotSet)
+	})
+}
+
+func TestContainer_Reset(t *testing.T) {
+	t.Parallel()
+	l := log.New(io.Discard)
+
+	t.Run("with files", func(t *testing.T) {
+		t.Parallel()
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, "config.yml", []byte(firstMockFilesYaml), 0o644))
+
+		c := config.NewFilesContainer(l, fs, "config.yml")
+		t.Cleanup(func() { _ = c.Close() })
+		c.GetViper().SetDefault("defaulted.key", "default")
+
+		var observed int32
+		c.AddObserverFunc(func(_ config.Containable, _ chan error) {
+			atomic.AddInt32(&observed, 1)
+		})
+
+		c.Set("yaml.key", "overridden")
+		c.Set("defaulted.key", "overridden")
+		c.Set("added.key", "set")
+
+		require.NoError(t, c.Reset())
+		assert.Equal(t, "value", c.GetString("yaml.key"))
+		assert.Equal(t, "default", c.GetString("defaulted.key"))
+		assert.False(t, c.IsSet("added.key"))
+		assert.Eventually(t, func() bool { return atomic.LoadInt32(&observed) == 1 }, time.Second, 10*time.Millisecond)
+
+		c.Set("yaml.key", "again")
+		require.NoError(t, c.Reset())
+		assert.Equal(t, "value", c.GetString("yaml.key"))
+		assert.Eventually(t, func() bool { return atomic.LoadInt32(&observed) == 2 }, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("with a reader", func(t *testing.T) {
+		t.Parallel()
+		c := config.NewReaderContainer(l, "yaml", strings.NewReader(firstMockFilesYaml))
+
+		c.Set("yaml.key", "overridden")
+		c.Set("yaml.extra", "added")
+		require.NoError(t, c.Reset())
+		assert.Equal(t, "value", c.GetString("yaml.key"))
+		assert.False(t, c.IsSet("yaml.extra"))
+
+		yaml, ok := c.Get("yaml").(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, "value", yaml["key"])
+		assert.ElementsMatch(t, []string{"key", "bool", "int", "float", "time", "duration"}, c.GetMapKeys("yaml"))
+	})
+
+	t.Run("when frozen", func(t *testing.T) {
+		t.Parallel()
+		c := config.NewReaderContainer(l, "yaml", strings.NewReader(firstMockFilesYaml))
+		c.Freeze()
+
+		require.ErrorIs(t, c.Reset(), config.ErrFrozen)
+	})
+}
+
+func TestContainer_SetFs(t *testing.T) {
+	t.Parallel()
+
+	l := log.New(io.Discard)
+	first := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(first, "config.yml", []byte(firstMockFilesYaml), 0o644))
+	second := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(second, "config.yml", []byte(secondMockFilesYaml), 0o644))
+
+	c := config.NewFilesContainer(l, first, "config.yml")
+	require.Equal(t, "value", c.GetString("yaml.key"))
+
+	c.SetFs(second)
+	assert.Equal(t, "value", c.GetString("yaml.key"), "already loaded config is unchanged")
+
+	require.NoError(t, c.GetViper().ReadInConfig())
+	assert.Equal(t, "value2", c.GetString("yaml.key"))
+}
+
+func TestContainer_Clone(t *testing.T) {
+	t.Parallel()
+
+	l := log.New(io.Discard)
+	c := config.NewReaderContainer(l, "yaml", strings.NewReader(firstMockFilesYaml))
+	c.AddObserverFunc(func(config.Containable, chan error) {})
+
+	clone := c.Clone()
+	assert.NotEqual(t, c.ID, clone.ID)
+	assert.Equal(t, "value", clone.GetString("yaml.key"))
+	assert.Empty(t, clone.GetObservers())
+
+	clone.Set("yaml.key", "overridden")
+	clone.Set("yaml.extra", "added")
+
+	assert.Equal(t, "overridden", clone.GetString("yaml.key"))
+	assert.Equal(t, "added", clone.GetString("yaml.extra"))
+	assert.Equal(t, "value", c.GetString("yaml.key"))
+	assert.False(t, c.GetViper().IsSet("yaml.extra"))
+	assert.Len(t, c.GetObservers(), 1)
+}
+
+func TestContainer_Clone_EnvPrefix(t *testing.T) {
+	t.Setenv("YAML_KEY", "unprefixed")
+	t.Setenv("MYAPP_YAML_KEY", "prefixed")
+	t.Setenv("MYAPP_BOUND", "bound")
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "first.yml", []byte(firstMockFilesYaml), 0o644))
+
+	l := log.New(io.Discard)
+	loaded, err := config.LoadWithOptions([]string{"first.yml"}, fs, l, config.LoadOptions{EnvPrefix: "MYAPP"})
+	require.NoError(t, err)
+	c := loaded.(*config.Container)
+	require.NoError(t, c.BindEnv("yaml.bound", "MYAPP_BOUND"))
+	c.RegisterDeprecation("yaml.int", "yaml.count")
+	c.Set("yaml.bool", false)
+
+	clone := c.Clone()
+	assert.Equal(t, "prefixed", clone.GetString("yaml.key"))
+	assert.Equal(t, "bound", clone.GetString("yaml.bound"))
+	assert.Equal(t, 1, clone.GetInt("yaml.count"))
+	assert.False(t, clone.GetBool("yaml.bool"))
+
+	info := clone.Explain("yaml.key")
+	assert.Equal(t, config.SourceEnv, info.Source)
+	assert.Equal(t, "MYAPP_YAML_KEY", info.EnvVar)
+	assert.Equal(t, config.SourceOverride, clone.Explain("yaml.bool").Source)
+
+	require.NoError(t, clone.Unset("yaml.bool"))
+	assert.True(t, clone.GetBool("yaml.bool"))
+	assert.False(t, c.GetBool("yaml.bool"))
+}
+
+func TestContainer_GetSlice(t *testing.T) {
+	t.Parallel()
+
+	l := log.New(io.Discard)
+	c := config.NewReaderContainer(l, "yaml", strings.NewReader(`servers:
+  - host: a
+    port: 1
+    tls:
+      enabled: true
+  - host: b
+    port: 2
+mixed:
+  - host: c
+  - "not an object"`))
+
+	t.Run("iterating a list of objects", func(t *testing.T) {
+		t.Parallel()
+		servers := c.GetSlice("servers")
+		require.Len(t, servers, 2)
+
+		assert.Equal(t, "a", servers[0].GetString("host"))
+		assert.Equal(t, 1, servers[0].GetInt("port"))
+		assert.True(t, servers[0].GetBool("tls.enabled"))
+		assert.Equal(t, "b", servers[1].GetString("host"))
+		assert.Equal(t, 2, servers[1].GetInt("port"))
+	})
+
+	t.Run("skipping non-object elements", func(t *testing.T) {
+		t.Parallel()
+		mixed := c.GetSlice("mixed")
+		require.Len(t, mixed, 1)
+		assert.Equal(t, "c", mixed[0].GetString("host"))
+
+		_, err := c.GetSliceStrict("mixed")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "mixed[1]")
+	})
+
+	t.Run("with missing key", func(t *testing.T) {
+		t.Parallel()
+		assert.Empty(t, c.GetSlice("missing"))
+	})
+}
+
+func TestContainer_Sub_MergedFiles(t *testing.T) {
+	t.Parallel()
+
+	l := log.New(io.Discard)
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "first.yml", []byte(firstMockFilesYaml), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "second.yml", []byte(secondMockFilesYaml), 0o644))
+
+	c := config.NewFilesContainer(l, fs, "first.yml", "second.yml")
+	c.Set("yaml.more.override", "set")
+
+	sub := c.Sub("yaml")
+	assert.Equal(t, "value2", sub.GetString("key"))
+	assert.True(t, sub.GetBool("bool"))
+	assert.Equal(t, "secondfile", sub.GetString("more.key2"))
+	assert.Equal(t, "set", sub.GetString("more.override"))
+
+	more := c.Sub("yaml.more")
+	assert.Equal(t, "secondfile", more.GetString("key2"))
+
+	assert.False(t, c.Sub("yaml.key").IsSet("anything"))
+}
+
+func TestContainer_Sub_Missing(t *testing.T) {
+	t.Parallel()
+
+	l := log.New(io.Discard)
+	c := config.NewReaderContainer(l, "yaml", strings.NewReader(secondMockFilesYaml))
+	s := c.Sub("does.not.exist")
+
+	require.NotNil(t, s)
+	assert.NotPanics(t, func() {
+		assert.Empty(t, s.GetString("anything"))
+		assert.False(t, s.Has("anything"))
+		assert.Empty(t, s.Sub("deeper").GetString("anything"))
+	})
+}
+
+func TestContainer_SubPath(t *testing.T) {
+	t.Parallel()
+
+	l := log.New(io.Discard)
+	c := config.NewReaderContainer(l, "yaml", strings.NewReader(secondMockFilesYaml))
+
+	t.Run("descending two levels", func(t *testing.T) {
+		t.Parallel()
+		s, err := c.SubPath("yaml.more")
+		require.NoError(t, err)
+		assert.Equal(t, "secondfile", s.GetString("key2"))
+	})
+
+	t.Run("with missing segment", func(t *testing.T) {
+		t.Parallel()
+		_, err := c.SubPath("yaml.missing.deeper")
+		require.ErrorIs(t, err, config.ErrKeyNotSet)
+		assert.Contains(t, err.Error(), `"yaml.missing"`)
+	})
+
+	t.Run("with scalar segment", func(t *testing.T) {
+		t.Parallel()
+		_, err := c.SubPath("yaml.key")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not a subtree")
+	})
+}
+
+func TestContainer_IndexedKeys(t *testing.T) {
+	t.Parallel()
+
+	l := log.New(io.Discard)
+	c := config.NewReaderContainer(l, "yaml", strings.NewReader(`features: [auth, logging]
+servers:
+  - host: "a.example.com"
+    port: 1
+  - host: "b.example.com"
+    port: 2`))
+
+	t.Run("with scalar element", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "auth", c.GetString("features[0]"))
+		assert.Equal(t, "logging", c.GetString("features[1]"))
+	})
+
+	t.Run("with nested element", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "b.example.com", c.GetString("servers[1].host"))
+		assert.Equal(t, 2, c.GetInt("servers[1].port"))
+	})
+
+	t.Run("with out of range index", func(t *testing.T) {
+		t.Parallel()
+		assert.Empty(t, c.GetString("features[5]"))
+		assert.Zero(t, c.GetInt("servers[9].port"))
+	})
+}
+
+func TestContainer_GetStringSliceUnique(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	err := afero.WriteFile(fs, "first.yml", []byte("x:\n  y: [a, b, a]\n  z: first"), 0o644)
+	require.NoError(t, err)
+
+	err = afero.WriteFile(fs, "second.yml", []byte("x:\n  y: [b, c, b, a, c]"), 0o644)
+	require.NoError(t, err)
+
+	l := log.New(io.Discard)
+	c := config.NewFilesContainer(l, fs, "first.yml", "second.yml")
+	s := c.Sub("x").(*config.Container)
+
+	assert.Equal(t, []string{"b", "c", "b", "a", "c"}, s.GetStringSlice("y"))
+	assert.Equal(t, []string{"b", "c", "a"}, s.GetStringSliceUnique("y"))
+	assert.Equal(t, "first", s.GetString("z"))
+}
+
+func TestContainer_CanonicalYAML(t *testing.T) {
+	t.Parallel()
+
+	l := log.New(io.Discard)
+	yamlSource := `zeta: last
+alpha:
+  nested:
+    b: 2
+    a: 1
+  list: [x, "y"]
+  enabled: true`
+	jsonSource := `{"alpha": {"enabled": true, "list": ["x", "y"], "nested": {"a": 1, "b": 2}}, "zeta": "last"}`
+
+	y := config.NewReaderContainer(l, "yaml", strings.NewReader(yamlSource))
+	j := config.NewReaderContainer(l, "json", strings.NewReader(jsonSource))
+
+	expected := `alpha:
+    enabled: true
+    list:
+        - x
+        - "y"
+    nested:
+        a: 1
+        b: 2
+zeta: last
+`
+
+	assert.Equal(t, expected, y.CanonicalYAML())
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, y.CanonicalYAML(), y.CanonicalYAML())
+	}
+	assert.Equal(t, y.CanonicalYAML(), j.CanonicalYAML())
+}
+
+func TestContainer_WithReadLogging(t *testing.T) {
+	t.Parallel()
+
+	t.Run("with read logging enabled", func(t *testing.T) {
+		t.Parallel()
+		buf := &bytes.Buffer{}
+		l := log.New(buf)
+		l.SetLevel(log.DebugLevel)
+
+		c := config.NewReaderContainer(l, "yaml", strings.NewReader(firstMockFilesYaml)).WithReadLogging()
+		assert.Equal(t, "value", c.GetString("yaml.key"))
+		assert.Contains(t, buf.String(), "config read")
+		assert.Contains(t, buf.String(), "key=yaml.key")
+		assert.Contains(t, buf.String(), "value=value")
+	})
+
+	t.Run("with read logging disabled", func(t *testing.T) {
+		t.Parallel()
+		buf := &bytes.Buffer{}
+		l := log.New(buf)
+		l.SetLevel(log.DebugLevel)
+
+		c := config.NewReaderContainer(l, "yaml", strings.NewReader(firstMockFilesYaml))
+		assert.Equal(t, "value", c.GetString("yaml.key"))
+		assert.NotContains(t, buf.String(), "config read")
+	})
+}
+
+func TestContainer_LogEffectiveConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("with secret keys redacted", func(t *testing.T) {
+		t.Parallel()
+		buf := &bytes.Buffer{}
+		l := log.New(buf)
+		l.SetLevel(log.DebugLevel)
+
+		c := config.NewReaderContainer(l, "yaml", strings.NewReader(redactMockYaml))
+		c.SetSecretKeys("*.password")
+		c.LogEffectiveConfig(log.DebugLevel)
+
+		out := buf.String()
+		assert.Contains(t, out, "Effective config")
+		assert.Contains(t, out, "db.example.com")
+		assert.Contains(t, out, "public")
+		assert.Contains(t, out, "REDACTED")
+		assert.NotContains(t, out, "hunter2")
+		assert.NotContains(t, out, "swordfish")
+	})
+
+	t.Run("with level below the logger's", func(t *testing.T) {
+		t.Parallel()
+		buf := &bytes.Buffer{}
+		l := log.New(buf)
+		l.SetLevel(log.InfoLevel)
+
+		c := config.NewReaderContainer(l, "yaml", strings.NewReader(redactMockYaml))
+		c.LogEffectiveConfig(log.DebugLevel)
+
+		assert.NotContains(t, buf.String(), "Effective config")
+	})
+}
+
 func TestContainer_GetViper(t *testing.T) {
 	t.Parallel()
 
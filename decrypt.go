@@ -0,0 +1,96 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/go-errors/errors"
+)
+
+// encryptedPrefix marks string values that should be passed through the decryptor when read.
+const encryptedPrefix = "enc:"
+
+// SetDecryptor set the function used to transparently decrypt string values prefixed with "enc:".
+// The prefix is stripped before the remaining ciphertext is passed to d.
+func (c *Container) SetDecryptor(d func(ciphertext string) (string, error)) {
+	if c.ignoreFrozen("SetDecryptor") {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.decryptor = d
+}
+
+// GetStringDecrypted get string value from config, decrypting it if it is prefixed with "enc:" and
+// returning any decryption error.
+func (c *Container) GetStringDecrypted(key string) (string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	s, err := c.decrypt(c.viper.GetString(c.lookupKey(key)))
+	if err != nil {
+		return "", errors.Errorf("unable to decrypt config key %q: %w", key, err)
+	}
+
+	return logged(c, key, s), nil
+}
+
+// decrypt pass s through the decryptor if one is set and s carries the encrypted prefix.
+func (c *Container) decrypt(s string) (string, error) {
+	if c.decryptor == nil || !strings.HasPrefix(s, encryptedPrefix) {
+		return s, nil
+	}
+
+	return c.decryptor(strings.TrimPrefix(s, encryptedPrefix))
+}
+
+// decryptValue decrypt every encrypted string within v, as decryptOrEmpty does, copying maps and lists
+// rather than changing the values held by viper.
+func (c *Container) decryptValue(key string, v interface{}) interface{} {
+	if c.decryptor == nil {
+		return v
+	}
+
+	switch t := v.(type) {
+	case string:
+		return c.decryptOrEmpty(key, t)
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, e := range t {
+			m[k] = c.decryptValue(key+"."+k, e)
+		}
+
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(t))
+		for i, e := range t {
+			l[i] = c.decryptValue(key, e)
+		}
+
+		return l
+	default:
+		return v
+	}
+}
+
+// decryptOrEmpty decrypt s, logging any failure and returning an empty string in its place.
+func (c *Container) decryptOrEmpty(key, s string) string {
+	plain, err := c.decrypt(s)
+	if err != nil {
+		c.logger.Error("unable to decrypt config value", "key", key, "error", err)
+
+		return ""
+	}
+
+	return plain
+}
+
+// decryptSlice decrypt each element of values in place.
+func (c *Container) decryptSlice(key string, values []string) []string {
+	for i, v := range values {
+		values[i] = c.decryptOrEmpty(key, v)
+	}
+
+	return values
+}
@@ -0,0 +1,124 @@
+package config_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/log"
+	"github.com/go-errors/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/phpboyscout/config"
+)
+
+var decryptMockYaml = `secrets:
+  password: "enc:hunter2"
+  plain: "visible"
+  broken: "enc:!invalid"
+  list: ["enc:one", "two"]
+  accounts:
+    - password: "enc:first"
+  endpoint: "enc:http://host"
+  level: "enc:info"
+  size: "enc:1kb"
+  ref: "user:${secrets.password}"
+  labels:
+    token: "enc:abc"
+    plain: "visible"
+  headers:
+    auth: ["enc:bearer", "basic"]`
+
+func upperDecryptor(ciphertext string) (string, error) {
+	if strings.HasPrefix(ciphertext, "!") {
+		return "", errors.New("bad ciphertext")
+	}
+
+	return strings.ToUpper(ciphertext), nil
+}
+
+func TestContainer_SetDecryptor(t *testing.T) {
+	t.Parallel()
+	l := log.New(io.Discard)
+	c := config.NewReaderContainer(l, "yaml", strings.NewReader(decryptMockYaml))
+	c.SetDecryptor(upperDecryptor)
+
+	t.Run("with prefixed value", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "HUNTER2", c.GetString("secrets.password"))
+	})
+
+	t.Run("with plain value", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "visible", c.GetString("secrets.plain"))
+	})
+
+	t.Run("with slice values", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, []string{"ONE", "two"}, c.GetStringSlice("secrets.list"))
+	})
+
+	t.Run("with decryption error", func(t *testing.T) {
+		t.Parallel()
+		assert.Empty(t, c.GetString("secrets.broken"))
+
+		_, err := c.GetStringDecrypted("secrets.broken")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "bad ciphertext")
+
+		v, err := c.GetStringDecrypted("secrets.password")
+		require.NoError(t, err)
+		assert.Equal(t, "HUNTER2", v)
+	})
+
+	t.Run("with derived containers", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "HUNTER2", c.Sub("secrets").GetString("password"))
+		assert.Equal(t, "HUNTER2", c.Clone().GetString("secrets.password"))
+
+		accounts := c.GetSlice("secrets.accounts")
+		require.Len(t, accounts, 1)
+		assert.Equal(t, "FIRST", accounts[0].GetString("password"))
+	})
+
+	t.Run("with other getters", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "HUNTER2", c.Get("secrets.password"))
+		secrets, ok := c.Get("secrets").(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, "HUNTER2", secrets["password"])
+		assert.Equal(t, []interface{}{"ONE", "two"}, secrets["list"])
+
+		labels, err := c.GetStringMapString("secrets.labels")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"token": "ABC", "plain": "visible"}, labels)
+		assert.Equal(t, map[string][]string{"auth": {"BEARER", "basic"}}, c.GetStringMapStringSlice("secrets.headers"))
+		assert.Equal(t, "user:HUNTER2", c.GetStringExpanded("secrets.ref"))
+
+		u, err := c.GetURL("secrets.endpoint")
+		require.NoError(t, err)
+		assert.Equal(t, "HOST", u.Host)
+
+		level, err := c.GetEnum("secrets.level", []string{"INFO", "DEBUG"})
+		require.NoError(t, err)
+		assert.Equal(t, "INFO", level)
+
+		size, err := c.GetBytes("secrets.size")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1000), size)
+
+		_, err = c.GetStringStrict("secrets.broken")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "bad ciphertext")
+
+		// the values held by viper are left encrypted.
+		assert.Equal(t, "enc:hunter2", c.GetViper().GetString("secrets.password"))
+	})
+
+	t.Run("without decryptor", func(t *testing.T) {
+		t.Parallel()
+		plain := config.NewReaderContainer(l, "yaml", strings.NewReader(decryptMockYaml))
+		assert.Equal(t, "enc:hunter2", plain.GetString("secrets.password"))
+	})
+}
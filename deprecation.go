@@ -0,0 +1,46 @@
+package config
+
+import (
+	"strings"
+)
+
+// deprecation a renamed key, read in place of its replacement until config is migrated.
+type deprecation struct {
+	oldKey string
+	newKey string
+}
+
+// RegisterDeprecation mark oldKey as renamed to newKey. A warning is logged if oldKey is set, and getters
+// reading newKey fall back to oldKey's value while newKey itself is unset.
+func (c *Container) RegisterDeprecation(oldKey, newKey string) {
+	if c.ignoreFrozen("RegisterDeprecation " + oldKey) {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	d := deprecation{
+		oldKey: strings.ToLower(c.canonicalKey(oldKey)),
+		newKey: strings.ToLower(c.canonicalKey(newKey)),
+	}
+	c.deprecations = append(c.deprecations, d)
+
+	if c.viper.IsSet(d.oldKey) {
+		c.logger.Warn("config key is deprecated", "key", oldKey, "replacement", newKey)
+	}
+}
+
+// lookupKey translate a key passed to a getter into the form held by viper, substituting a deprecated key
+// when the requested one is unset.
+func (c *Container) lookupKey(key string) string {
+	key = c.canonicalKey(key)
+
+	for _, d := range c.deprecations {
+		if strings.EqualFold(d.newKey, key) && !c.viper.IsSet(key) && c.viper.IsSet(d.oldKey) {
+			return d.oldKey
+		}
+	}
+
+	return key
+}
@@ -0,0 +1,59 @@
+package config_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/log"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/phpboyscout/config"
+)
+
+func TestContainer_RegisterDeprecation(t *testing.T) {
+	t.Parallel()
+
+	t.Run("with old key set", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		c := config.NewReaderContainer(log.New(&buf), "yaml", strings.NewReader(`server:
+  port: 8080`))
+
+		c.RegisterDeprecation("server.port", "server.listen.port")
+
+		assert.Equal(t, 8080, c.GetInt("server.listen.port"))
+		assert.Equal(t, "8080", c.GetString("server.listen.port"))
+		assert.True(t, c.IsSet("server.listen.port"))
+		assert.Equal(t, 1, strings.Count(buf.String(), "config key is deprecated"))
+		assert.Contains(t, buf.String(), "server.listen.port")
+	})
+
+	t.Run("with new key set", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		c := config.NewReaderContainer(log.New(&buf), "yaml", strings.NewReader(`server:
+  port: 8080
+  listen:
+    port: 9090`))
+
+		c.RegisterDeprecation("server.port", "server.listen.port")
+
+		assert.Equal(t, 9090, c.GetInt("server.listen.port"))
+		c.Set("server.listen.port", 7070)
+		assert.Equal(t, 7070, c.GetInt("server.listen.port"))
+		assert.Equal(t, 8080, c.GetInt("server.port"))
+	})
+
+	t.Run("with neither key set", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		c := config.NewReaderContainer(log.New(&buf), "yaml", strings.NewReader(`server:
+  host: localhost`))
+
+		c.RegisterDeprecation("server.port", "server.listen.port")
+
+		assert.Zero(t, c.GetInt("server.listen.port"))
+		assert.NotContains(t, buf.String(), "deprecated")
+	})
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"reflect"
+	"sort"
+)
+
+// KeyChange a single config key whose value differs between two configs.
+// Old is nil for added keys and New is nil for removed keys.
+type KeyChange struct {
+	Key string
+	Old interface{}
+	New interface{}
+}
+
+// Diff compare two configs, returning every added, removed or changed key sorted by key.
+func Diff(old, next Containable) []KeyChange {
+	return diffSettings(old.GetViper().AllSettings(), next.GetViper().AllSettings())
+}
+
+// diffSettings compare two sets of nested settings as Diff.
+func diffSettings(old, next map[string]interface{}) []KeyChange {
+	before := flattenSettings(old, "", make(map[string]interface{}))
+	after := flattenSettings(next, "", make(map[string]interface{}))
+
+	changes := make([]KeyChange, 0)
+	for k, o := range before {
+		n, ok := after[k]
+		if !ok || !reflect.DeepEqual(o, n) {
+			changes = append(changes, KeyChange{Key: k, Old: o, New: n})
+		}
+	}
+
+	for k, n := range after {
+		if _, ok := before[k]; !ok {
+			changes = append(changes, KeyChange{Key: k, New: n})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+
+	return changes
+}
+
+// flattenSettings collapse nested settings into dotted keys, treating lists and scalars as leaf values.
+func flattenSettings(m map[string]interface{}, prefix string, out map[string]interface{}) map[string]interface{} {
+	for k, v := range m {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+
+		if nested, ok := v.(map[string]interface{}); ok && len(nested) > 0 {
+			flattenSettings(nested, key, out)
+
+			continue
+		}
+
+		out[key] = v
+	}
+
+	return out
+}
@@ -0,0 +1,57 @@
+package config_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/log"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/phpboyscout/config"
+)
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+	l := log.New(io.Discard)
+
+	old := config.NewReaderContainer(l, "yaml", strings.NewReader(`server:
+  host: "localhost"
+  port: 80
+  tags: [a, b]
+removed:
+  key: "gone"
+same: "unchanged"`))
+
+	updated := config.NewReaderContainer(l, "yaml", strings.NewReader(`server:
+  host: "example.com"
+  port: 80
+  tags: [a, b, c]
+added:
+  nested:
+    key: "new"
+same: "unchanged"`))
+
+	t.Run("with added, removed and changed keys", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, []config.KeyChange{
+			{Key: "added.nested.key", Old: nil, New: "new"},
+			{Key: "removed.key", Old: "gone", New: nil},
+			{Key: "server.host", Old: "localhost", New: "example.com"},
+			{Key: "server.tags", Old: []interface{}{"a", "b"}, New: []interface{}{"a", "b", "c"}},
+		}, config.Diff(old, updated))
+	})
+
+	t.Run("is deterministic", func(t *testing.T) {
+		t.Parallel()
+		first := config.Diff(old, updated)
+		for i := 0; i < 10; i++ {
+			assert.Equal(t, first, config.Diff(old, updated))
+		}
+	})
+
+	t.Run("with identical configs", func(t *testing.T) {
+		t.Parallel()
+		assert.Empty(t, config.Diff(old, old))
+	})
+}
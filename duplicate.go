@@ -0,0 +1,74 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-errors/errors"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrDuplicateKey returned in strict mode when a YAML mapping defines the same key more than once.
+var ErrDuplicateKey = errors.New("duplicate config key")
+
+// checkDuplicateKeys parse file, if it is YAML, and error on the first key defined twice in one mapping.
+func checkDuplicateKeys(fs afero.Fs, file, format string) error {
+	if format == "" {
+		format = strings.ToLower(strings.TrimPrefix(filepath.Ext(file), "."))
+	}
+
+	if format != "yaml" && format != "yml" {
+		return nil
+	}
+
+	b, err := afero.ReadFile(fs, file)
+	if err != nil {
+		return errors.WrapPrefix(err, "unable to read config file "+file, 0)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return errors.WrapPrefix(err, "unable to parse config file "+file, 0)
+	}
+
+	if err := findDuplicateKey(&doc, ""); err != nil {
+		return errors.Errorf("%s: %w", file, err)
+	}
+
+	return nil
+}
+
+// findDuplicateKey walk n depth first, reporting the dotted path of the first duplicated mapping key.
+func findDuplicateKey(n *yaml.Node, prefix string) error {
+	if n.Kind != yaml.MappingNode {
+		for _, child := range n.Content {
+			if err := findDuplicateKey(child, prefix); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	seen := make(map[string]int, len(n.Content)/2)
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k, v := n.Content[i], n.Content[i+1]
+		path := k.Value
+		if prefix != "" {
+			path = fmt.Sprintf("%s.%s", prefix, k.Value)
+		}
+
+		if line, ok := seen[k.Value]; ok {
+			return errors.Errorf("%w %q at line %d, first defined at line %d", ErrDuplicateKey, path, k.Line, line)
+		}
+		seen[k.Value] = k.Line
+
+		if err := findDuplicateKey(v, path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/go-errors/errors"
+)
+
+// EtcdOptions connection options for LoadEtcdWithOptions.
+type EtcdOptions struct {
+	// TLS client TLS configuration, e.g. for mutual TLS. Ignored when Client is set.
+	TLS *tls.Config
+	// Client HTTP client used for requests. Defaults to a client with a sane timeout.
+	Client *http.Client
+}
+
+// etcdProvider RemoteProvider reading values through the etcd v3 JSON gateway, trying each endpoint in turn.
+type etcdProvider struct {
+	endpoints []string
+	client    *http.Client
+}
+
+// NewEtcdProvider return a RemoteProvider reading keys from the etcd v3 cluster at endpoints, e.g.
+// "https://127.0.0.1:2379".
+func NewEtcdProvider(endpoints []string, opts EtcdOptions) RemoteProvider {
+	client := opts.Client
+	if client == nil {
+		client = &http.Client{Timeout: defaultHTTPTimeout}
+		if opts.TLS != nil {
+			client.Transport = &http.Transport{TLSClientConfig: opts.TLS}
+		}
+	}
+
+	return &etcdProvider{endpoints: endpoints, client: client}
+}
+
+// etcdRangeResponse subset of the etcd v3 range response holding the matched values.
+type etcdRangeResponse struct {
+	Kvs []struct {
+		Value string `json:"value"`
+	} `json:"kvs"`
+}
+
+// Get read the value stored at key from the first endpoint that responds.
+func (p *etcdProvider) Get(ctx context.Context, key string) ([]byte, error) {
+	body, err := json.Marshal(map[string]string{"key": base64.StdEncoding.EncodeToString([]byte(key))})
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+
+	var errs []error
+	for _, endpoint := range p.endpoints {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(endpoint, "/")+"/v3/kv/range", bytes.NewReader(body))
+		if err != nil {
+			errs = append(errs, errors.WrapPrefix(err, "invalid etcd endpoint "+endpoint, 0))
+
+			continue
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		b, err := doHTTP(p.client, req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			errs = append(errs, err)
+
+			continue
+		}
+
+		var resp etcdRangeResponse
+		if err := json.Unmarshal(b, &resp); err != nil {
+			return nil, errors.WrapPrefix(err, "unable to decode etcd response from "+endpoint, 0)
+		}
+
+		if len(resp.Kvs) == 0 {
+			return nil, errors.Errorf("%w: etcd key %s", ErrKeyNotSet, key)
+		}
+
+		value, err := base64.StdEncoding.DecodeString(resp.Kvs[0].Value)
+		if err != nil {
+			return nil, errors.WrapPrefix(err, "unable to decode etcd value for "+key, 0)
+		}
+
+		return value, nil
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+// LoadEtcd Initialise a configuration container from the value stored at key in etcd v3, parsed in the
+// given format. ctx bounds the request, across every endpoint tried.
+func LoadEtcd(ctx context.Context, endpoints []string, key, format string, logger *log.Logger) (Containable, error) {
+	return LoadEtcdWithOptions(ctx, endpoints, key, format, logger, EtcdOptions{})
+}
+
+// LoadEtcdWithOptions Initialise a configuration container from the value stored at key in etcd v3 as
+// LoadEtcd, with connection controlled by opts.
+func LoadEtcdWithOptions(ctx context.Context, endpoints []string, key, format string, logger *log.Logger, opts EtcdOptions) (Containable, error) {
+	if len(endpoints) == 0 {
+		return nil, errors.New("no etcd endpoints given")
+	}
+
+	return LoadRemote(ctx, NewEtcdProvider(endpoints, opts), key, format, logger)
+}
@@ -0,0 +1,92 @@
+package config_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/charmbracelet/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/phpboyscout/config"
+)
+
+func newEtcdGateway(t *testing.T, values map[string]string) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Key string `json:"key"`
+		}
+		if r.URL.Path != "/v3/kv/range" || json.NewDecoder(r.Body).Decode(&req) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		key, _ := base64.StdEncoding.DecodeString(req.Key)
+		value, ok := values[string(key)]
+		if !ok {
+			_, _ = io.WriteString(w, `{"header": {}}`)
+
+			return
+		}
+
+		_, _ = fmt.Fprintf(w, `{"kvs": [{"key": %q, "value": %q}]}`, req.Key, base64.StdEncoding.EncodeToString([]byte(value)))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestLoadEtcd(t *testing.T) {
+	t.Parallel()
+	logger := log.New(io.Discard)
+	srv := newEtcdGateway(t, map[string]string{"/app/config": firstMockFilesYaml})
+
+	t.Run("with existing key", func(t *testing.T) {
+		t.Parallel()
+		c, err := config.LoadEtcd(context.Background(), []string{srv.URL}, "/app/config", "yaml", logger)
+		require.NoError(t, err)
+		assert.Equal(t, "value", c.GetString("yaml.key"))
+	})
+
+	t.Run("with unavailable first endpoint", func(t *testing.T) {
+		t.Parallel()
+		down := httptest.NewServer(http.NotFoundHandler())
+		down.Close()
+
+		c, err := config.LoadEtcdWithOptions(context.Background(), []string{down.URL, srv.URL}, "/app/config", "yaml", logger, config.EtcdOptions{
+			Client: srv.Client(),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "value", c.GetString("yaml.key"))
+	})
+
+	t.Run("with missing key", func(t *testing.T) {
+		t.Parallel()
+		_, err := config.LoadEtcd(context.Background(), []string{srv.URL}, "/app/missing", "yaml", logger)
+		require.ErrorIs(t, err, config.ErrKeyNotSet)
+	})
+
+	t.Run("without endpoints", func(t *testing.T) {
+		t.Parallel()
+		_, err := config.LoadEtcd(context.Background(), nil, "/app/config", "yaml", logger)
+		require.Error(t, err)
+	})
+
+	t.Run("with a cancelled context", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := config.LoadEtcd(ctx, []string{srv.URL}, "/app/config", "yaml", logger)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+// Source layer that provided a config value, in viper's order of precedence.
+type Source string
+
+const (
+	// SourceOverride value applied with Set.
+	SourceOverride Source = "override"
+	// SourceFlag value from a bound command line flag.
+	SourceFlag Source = "flag"
+	// SourceEnv value from an environment variable.
+	SourceEnv Source = "env"
+	// SourceFile value from a loaded config file or reader.
+	SourceFile Source = "file"
+	// SourceDefault value from a registered default.
+	SourceDefault Source = "default"
+	// SourceUnset key has no value from any source.
+	SourceUnset Source = "unset"
+)
+
+// SourceInfo resolved value of a key along with the source that provided it.
+type SourceInfo struct {
+	Key    string
+	Value  interface{}
+	Source Source
+	// EnvVar name of the environment variable consulted for the key.
+	EnvVar string
+}
+
+// Explain report the resolved value of key and which source provided it, useful when an environment
+// variable or flag unexpectedly shadows a file value. Secret values are redacted.
+func (c *Container) Explain(key string) SourceInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	k := strings.ToLower(c.lookupKey(key))
+	info := SourceInfo{
+		Key:    key,
+		Value:  c.viper.Get(k),
+		Source: SourceUnset,
+		EnvVar: c.envVar(k),
+	}
+
+	if info.Value != nil && c.isSecret(key) {
+		info.Value = redactedValue
+	} else {
+		info.Value = c.redactValue(info.Value, k)
+	}
+
+	changed, hasFlag := c.flagChanged[k]
+	_, overridden := c.overrides[k]
+
+	switch {
+	case overridden:
+		info.Source = SourceOverride
+	case hasFlag && changed():
+		info.Source = SourceFlag
+	case os.Getenv(info.EnvVar) != "":
+		info.Source = SourceEnv
+	case c.viper.InConfig(k):
+		info.Source = SourceFile
+	case c.viper.IsSet(k):
+		info.Source = SourceDefault
+	}
+
+	return info
+}
+
+// envVar name of the environment variable consulted for key: the first set variable explicitly bound with
+// BindEnv, or otherwise the one AutomaticEnv derives from the key.
+func (c *Container) envVar(key string) string {
+	if names, ok := c.envBindings[key]; ok {
+		for _, name := range names {
+			if os.Getenv(name) != "" {
+				return name
+			}
+		}
+
+		return names[0]
+	}
+
+	name := strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+	if c.envPrefix != "" {
+		name = strings.ToUpper(c.envPrefix) + "_" + name
+	}
+
+	return name
+}
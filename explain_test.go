@@ -0,0 +1,80 @@
+package config_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/afero"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/phpboyscout/config"
+)
+
+func TestContainer_Explain(t *testing.T) {
+	t.Setenv("YAML_KEY", "from-env")
+
+	l := log.New(io.Discard)
+	c := config.NewReaderContainer(l, "yaml", strings.NewReader(firstMockFilesYaml))
+	c.GetViper().SetDefault("defaulted.key", "default")
+	c.Set("override.key", "set")
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.Int("yaml.int", 0, "")
+	require.NoError(t, c.BindPFlags(flags))
+	require.NoError(t, flags.Parse([]string{"--yaml.int=7"}))
+
+	tests := []struct {
+		key    string
+		value  interface{}
+		source config.Source
+	}{
+		{"yaml.key", "from-env", config.SourceEnv},
+		{"yaml.bool", true, config.SourceFile},
+		{"yaml.int", 7, config.SourceFlag},
+		{"defaulted.key", "default", config.SourceDefault},
+		{"override.key", "set", config.SourceOverride},
+		{"missing.key", nil, config.SourceUnset},
+	}
+
+	for _, tt := range tests {
+		info := c.Explain(tt.key)
+		assert.Equal(t, tt.value, info.Value, tt.key)
+		assert.Equal(t, tt.source, info.Source, tt.key)
+	}
+
+	assert.Equal(t, "YAML_KEY", c.Explain("yaml.key").EnvVar)
+}
+
+func TestContainer_Explain_EnvPrefix(t *testing.T) {
+	t.Setenv("APP_YAML_KEY", "from-env")
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "first.yml", []byte(firstMockFilesYaml), 0o644))
+
+	l := log.New(io.Discard)
+	c, err := config.LoadWithOptions([]string{"first.yml"}, fs, l, config.LoadOptions{EnvPrefix: "APP"})
+	require.NoError(t, err)
+
+	info := c.(*config.Container).Explain("yaml.key")
+	assert.Equal(t, "from-env", info.Value)
+	assert.Equal(t, config.SourceEnv, info.Source)
+	assert.Equal(t, "APP_YAML_KEY", info.EnvVar)
+}
+
+func TestContainer_Explain_Secrets(t *testing.T) {
+	t.Parallel()
+	l := log.New(io.Discard)
+	c := config.NewReaderContainer(l, "yaml", strings.NewReader(redactMockYaml))
+	c.SetSecretKeys("*.password")
+
+	assert.Equal(t, "***REDACTED***", c.Explain("database.password").Value)
+	assert.Equal(t, map[string]interface{}{
+		"host":     "db.example.com",
+		"password": "***REDACTED***",
+	}, c.Explain("Database").Value)
+	assert.Equal(t, config.SourceFile, c.Explain("database").Source)
+}
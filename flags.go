@@ -0,0 +1,144 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-errors/errors"
+	"github.com/spf13/pflag"
+)
+
+// BindPFlags bind a full pflag FlagSet so that flags override config, using each flag's name as the config key.
+// Precedence from highest to lowest is: flag > env > file > default.
+func (c *Container) BindPFlags(flags *pflag.FlagSet) error {
+	if err := c.frozenErr("BindPFlags"); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	err := c.viper.BindPFlags(flags)
+	c.mu.Unlock()
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+
+	flags.VisitAll(func(f *pflag.Flag) {
+		c.recordFlag(f.Name, func() bool { return f.Changed })
+	})
+
+	return nil
+}
+
+// BindPFlag bind a single pflag to the given config key so that the flag overrides config when set.
+// Precedence from highest to lowest is: flag > env > file > default.
+func (c *Container) BindPFlag(key string, flag *pflag.Flag) error {
+	if err := c.frozenErr("BindPFlag " + key); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	err := c.viper.BindPFlag(key, flag)
+	c.mu.Unlock()
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+
+	c.recordFlag(key, func() bool { return flag.Changed })
+
+	return nil
+}
+
+// BindEnv bind key to one or more explicitly named environment variables, e.g.
+// BindEnv("database.password", "DB_PASS"). The first variable that is set wins. With no names, key is bound
+// to its upper-cased name with any env prefix applied. Explicit binds apply whether or not automatic env
+// lookup is in effect. Precedence is the same as for automatic env: flag > env > file > default.
+func (c *Container) BindEnv(input ...string) error {
+	if len(input) == 0 {
+		return errors.New("BindEnv requires a config key")
+	}
+
+	if err := c.frozenErr("BindEnv " + input[0]); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	input = append([]string{c.canonicalKey(input[0])}, input[1:]...)
+	if err := c.viper.BindEnv(input...); err != nil {
+		return errors.Wrap(err, 0)
+	}
+
+	if len(input) > 1 {
+		if c.envBindings == nil {
+			c.envBindings = make(map[string][]string)
+		}
+		c.envBindings[strings.ToLower(input[0])] = input[1:]
+	}
+
+	return nil
+}
+
+// RequireEnv check that each key is provided by an environment variable, using the same naming as automatic
+// env lookup and any BindEnv mappings, and error listing every key that is not. Values from files or other
+// sources don't count, enforcing a policy of secrets only ever coming from the environment.
+func (c *Container) RequireEnv(keys ...string) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	missing := make([]string, 0)
+	for _, key := range keys {
+		name := c.envVar(strings.ToLower(c.canonicalKey(key)))
+		if os.Getenv(name) == "" {
+			missing = append(missing, fmt.Sprintf("%s (%s)", key, name))
+		}
+	}
+
+	if len(missing) > 0 {
+		return errors.Errorf("%w: required environment variables missing for %s", ErrKeyNotSet, strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// BindStdFlags apply flags from a standard library FlagSet that were explicitly set as config overrides.
+// Flag names have "-" mapped to "." so that "-database-host" overrides "database.host".
+func (c *Container) BindStdFlags(fs *flag.FlagSet) {
+	if c.ignoreFrozen("BindStdFlags") {
+		return
+	}
+
+	fs.Visit(func(f *flag.Flag) {
+		var value interface{} = f.Value.String()
+		if g, ok := f.Value.(flag.Getter); ok {
+			value = g.Get()
+		}
+
+		key := strings.ToLower(c.canonicalKey(strings.ReplaceAll(f.Name, "-", ".")))
+		c.mu.Lock()
+		if c.stdFlags == nil {
+			c.stdFlags = make(map[string]interface{})
+		}
+		// kept apart from Set overrides, so that Unset and Reset can replay them after clearing a shared root.
+		c.stdFlags[key] = value
+		c.viper.Set(key, value)
+		c.mu.Unlock()
+		c.recordFlag(key, func() bool { return true })
+	})
+
+	c.refreshReloadables()
+}
+
+// recordFlag remember that key is bound to a flag, with changed reporting whether the flag was set, so that
+// Explain can attribute values to it.
+func (c *Container) recordFlag(key string, changed func() bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.flagChanged == nil {
+		c.flagChanged = make(map[string]func() bool)
+	}
+	c.flagChanged[strings.ToLower(key)] = changed
+}
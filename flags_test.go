@@ -0,0 +1,133 @@
+package config_test
+
+import (
+	"flag"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/phpboyscout/config"
+)
+
+var flagsMockYaml = `database:
+  host: "file-host"
+  port: 5432`
+
+func TestContainer_BindPFlags(t *testing.T) {
+	t.Parallel()
+	l := log.New(io.Discard)
+
+	t.Run("with flag set on command line", func(t *testing.T) {
+		t.Parallel()
+		c := config.NewReaderContainer(l, "yaml", strings.NewReader(flagsMockYaml))
+
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		flags.String("database.host", "flag-default", "database host")
+		flags.Int("database.port", 1, "database port")
+		require.NoError(t, flags.Parse([]string{"--database.host=flag-host"}))
+
+		require.NoError(t, c.BindPFlags(flags))
+		assert.Equal(t, "flag-host", c.GetString("database.host"))
+		assert.Equal(t, 5432, c.GetInt("database.port"))
+	})
+
+	t.Run("with single flag bound to key", func(t *testing.T) {
+		t.Parallel()
+		c := config.NewReaderContainer(l, "yaml", strings.NewReader(flagsMockYaml))
+
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		flags.String("host", "", "database host")
+		require.NoError(t, flags.Parse([]string{"--host", "bound-host"}))
+
+		require.NoError(t, c.BindPFlag("database.host", flags.Lookup("host")))
+		assert.Equal(t, "bound-host", c.GetString("database.host"))
+	})
+
+	t.Run("with nil flag", func(t *testing.T) {
+		t.Parallel()
+		c := config.NewReaderContainer(l, "yaml", strings.NewReader(flagsMockYaml))
+		require.Error(t, c.BindPFlag("database.host", nil))
+	})
+}
+
+func TestContainer_BindStdFlags(t *testing.T) {
+	t.Parallel()
+	l := log.New(io.Discard)
+	c := config.NewReaderContainer(l, "yaml", strings.NewReader(flagsMockYaml))
+
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.String("database-host", "flag-default", "database host")
+	flags.Int("database-port", 1, "database port")
+	flags.Bool("debug", false, "debug mode")
+	require.NoError(t, flags.Parse([]string{"-database-host=flag-host", "-debug"}))
+
+	c.BindStdFlags(flags)
+
+	assert.Equal(t, "flag-host", c.GetString("database.host"))
+	assert.Equal(t, 5432, c.GetInt("database.port"))
+	assert.True(t, c.GetBool("debug"))
+}
+
+func TestContainer_BindStdFlags_UnsetSibling(t *testing.T) {
+	t.Parallel()
+	l := log.New(io.Discard)
+	c := config.NewReaderContainer(l, "yaml", strings.NewReader(flagsMockYaml))
+
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.String("database-host", "", "database host")
+	require.NoError(t, flags.Parse([]string{"-database-host=flag-host"}))
+	c.BindStdFlags(flags)
+
+	c.Set("database.port", 6543)
+	require.NoError(t, c.Unset("database.port"))
+	assert.Equal(t, "flag-host", c.GetString("database.host"))
+	assert.Equal(t, 5432, c.GetInt("database.port"))
+
+	c.Set("database.port", 6543)
+	c.Set("database.host", "override")
+	require.NoError(t, c.Reset())
+	assert.Equal(t, "flag-host", c.GetString("database.host"))
+	assert.Equal(t, 5432, c.GetInt("database.port"))
+	assert.Equal(t, config.SourceFlag, c.Explain("database.host").Source)
+}
+
+func TestContainer_BindEnv(t *testing.T) {
+	t.Setenv("DB_PASS", "from-env")
+	t.Setenv("YAML_KEY", "automatic")
+
+	l := log.New(io.Discard)
+	c := config.NewReaderContainer(l, "yaml", strings.NewReader(firstMockFilesYaml))
+
+	require.NoError(t, c.BindEnv("database.password", "DB_PASSWORD", "DB_PASS"))
+	assert.Equal(t, "from-env", c.GetString("database.password"))
+	assert.Equal(t, "automatic", c.GetString("yaml.key"))
+
+	info := c.Explain("database.password")
+	assert.Equal(t, config.SourceEnv, info.Source)
+	assert.Equal(t, "DB_PASS", info.EnvVar)
+
+	require.Error(t, c.BindEnv())
+}
+
+func TestContainer_RequireEnv(t *testing.T) {
+	t.Setenv("DATABASE_PASSWORD", "from-env")
+	t.Setenv("API_SECRET", "bound")
+
+	l := log.New(io.Discard)
+	c := config.NewReaderContainer(l, "yaml", strings.NewReader(`database:
+  token: "from-file"`))
+	require.NoError(t, c.BindEnv("api.token", "API_SECRET"))
+
+	require.NoError(t, c.RequireEnv("database.password", "api.token"))
+
+	err := c.RequireEnv("database.password", "database.token", "signing.key")
+	require.ErrorIs(t, err, config.ErrKeyNotSet)
+	assert.Contains(t, err.Error(), "database.token (DATABASE_TOKEN)")
+	assert.Contains(t, err.Error(), "signing.key (SIGNING_KEY)")
+	assert.NotContains(t, err.Error(), "database.password")
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/go-errors/errors"
+	"github.com/spf13/afero"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// FormatDecoder parse the raw contents of a config file into a settings map.
+type FormatDecoder func([]byte) (map[string]interface{}, error)
+
+var (
+	formatsMu sync.RWMutex
+	formats   = make(map[string]FormatDecoder)
+)
+
+// RegisterFormat register a decoder for config files with the given extension, e.g. "kv" or ".kv", so they
+// can be loaded alongside the formats viper supports natively. Registering an extension again replaces its
+// decoder. Registration is global and is intended to happen during program initialisation.
+func RegisterFormat(ext string, decode FormatDecoder) {
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+
+	formats[strings.ToLower(strings.TrimPrefix(ext, "."))] = decode
+}
+
+// formatDecoder return the registered decoder for the extension of path, if any.
+func formatDecoder(path string) (FormatDecoder, bool) {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+
+	decode, ok := formats[strings.ToLower(strings.TrimPrefix(filepath.Ext(path), "."))]
+
+	return decode, ok
+}
+
+// formatParseError failure of a registered decoder, treated as a parse failure alongside
+// viper.ConfigParseError, which can't be built outside viper.
+type formatParseError struct {
+	path string
+	err  error
+}
+
+func (e formatParseError) Error() string {
+	return fmt.Sprintf("unable to parse %s: %s", e.path, e.err)
+}
+
+func (e formatParseError) Unwrap() error {
+	return e.err
+}
+
+// decodeRegistered parse b with the decoder registered for the extension of path. ok is false when no
+// decoder is registered. Decode failures are reported as formatParseError.
+func decodeRegistered(path string, b []byte) (settings map[string]interface{}, ok bool, err error) {
+	decode, ok := formatDecoder(path)
+	if !ok {
+		return nil, false, nil
+	}
+
+	settings, err = decode(b)
+	if err != nil {
+		return nil, true, errors.Wrap(formatParseError{path: path, err: err}, 0)
+	}
+
+	return settings, true, nil
+}
+
+// readConfigFile read f into v, replacing its file config or, if merge is set, merging over it. Files with a
+// registered format are decoded and merged with MergeConfigMap; everything else is left to viper.
+func readConfigFile(v *viper.Viper, fs afero.Fs, f string, merge bool) error {
+	v.SetConfigFile(f)
+
+	if _, ok := formatDecoder(f); !ok {
+		if merge {
+			return v.MergeInConfig()
+		}
+
+		return v.ReadInConfig()
+	}
+
+	b, err := afero.ReadFile(fs, f)
+	if err != nil {
+		return err
+	}
+
+	settings, _, err := decodeRegistered(f, b)
+	if err != nil {
+		return err
+	}
+
+	if !merge {
+		// ReadConfig with an unrecognised type simply clears the file config, as ReadInConfig would.
+		if err := v.ReadConfig(bytes.NewReader(nil)); err != nil {
+			return err
+		}
+	}
+
+	return v.MergeConfigMap(settings)
+}
+
+// reencodeRegistered convert b to YAML if path has a registered format, so it can be merged with the YAML
+// readers of embedded config. Other content is returned unchanged.
+func reencodeRegistered(path string, b []byte) ([]byte, error) {
+	settings, ok, err := decodeRegistered(path, b)
+	if !ok || err != nil {
+		return b, err
+	}
+
+	out, err := yaml.Marshal(settings)
+	if err != nil {
+		return nil, errors.WrapPrefix(err, "unable to convert config "+path, 0)
+	}
+
+	return out, nil
+}
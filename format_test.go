@@ -0,0 +1,86 @@
+package config_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/charmbracelet/log"
+	"github.com/go-errors/errors"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/phpboyscout/config"
+)
+
+func init() {
+	config.RegisterFormat(".kv", func(b []byte) (map[string]interface{}, error) {
+		settings := make(map[string]interface{})
+		for _, line := range strings.Split(strings.TrimSpace(string(b)), "\n") {
+			k, v, ok := strings.Cut(line, "=")
+			if !ok {
+				return nil, errors.Errorf("malformed line %q", line)
+			}
+			settings[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+
+		return settings, nil
+	})
+}
+
+func TestRegisterFormat(t *testing.T) {
+	t.Parallel()
+	logger := log.New(io.Discard)
+
+	t.Run("with a registered format through Load", func(t *testing.T) {
+		t.Parallel()
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, "app.kv", []byte("name = kv-app\nregion=eu"), 0o644))
+
+		c, err := config.Load([]string{"app.kv"}, fs, logger, false)
+		require.NoError(t, err)
+		assert.Equal(t, "kv-app", c.GetString("name"))
+		assert.Equal(t, "eu", c.GetString("region"))
+	})
+
+	t.Run("with a registered format merged with yaml", func(t *testing.T) {
+		t.Parallel()
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, "app.yml", []byte("name: yaml-app\nport: 8080"), 0o644))
+		require.NoError(t, afero.WriteFile(fs, "app.kv", []byte("name=kv-app"), 0o644))
+
+		c, err := config.Load([]string{"app.yml", "app.kv"}, fs, logger, false)
+		require.NoError(t, err)
+		assert.Equal(t, "kv-app", c.GetString("name"))
+		assert.Equal(t, 8080, c.GetInt("port"))
+	})
+
+	t.Run("with a malformed registered format", func(t *testing.T) {
+		t.Parallel()
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, "app.kv", []byte("not a pair"), 0o644))
+		buf := &bytes.Buffer{}
+
+		c, err := config.Load([]string{"app.kv"}, fs, log.New(buf), false)
+		require.NoError(t, err)
+		assert.True(t, c.(*config.Container).HasLoadErrors())
+		assert.Contains(t, buf.String(), "Could not parse the config file")
+		assert.NotContains(t, buf.String(), "Could not read")
+	})
+
+	t.Run("with a registered format through LoadEmbed", func(t *testing.T) {
+		t.Parallel()
+		embed := fstest.MapFS{
+			"config/app.yml": {Data: []byte("name: yaml-app\nport: 8080")},
+			"config/app.kv":  {Data: []byte("name=kv-app")},
+		}
+
+		c, err := config.LoadEmbed([]string{"config/app.yml", "config/app.kv"}, embed, logger)
+		require.NoError(t, err)
+		assert.Equal(t, "kv-app", c.GetString("name"))
+		assert.Equal(t, 8080, c.GetInt("port"))
+	})
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"github.com/go-errors/errors"
+)
+
+// ErrFrozen returned when mutating a container that has been frozen.
+var ErrFrozen = errors.New("config container is frozen")
+
+// Freeze make the container read-only. Afterwards Unset, Reset and the BindEnv and BindPFlag functions
+// return ErrFrozen, while Set, BindStdFlags, SetKeyNormalizer, SetSecretKeys, SetDecryptor, SetFs,
+// RegisterDeprecation and observer and finalizer registration are ignored with an error logged. Getters are
+// unaffected.
+func (c *Container) Freeze() {
+	c.frozen.Store(true)
+}
+
+// FreezeStrict make the container read-only as Freeze, but panic with ErrFrozen on any attempted mutation
+// so that code mutating shared config fails fast, e.g. in tests.
+func (c *Container) FreezeStrict() {
+	c.freezePanics.Store(true)
+	c.frozen.Store(true)
+}
+
+// Frozen reports whether the container has been frozen.
+func (c *Container) Frozen() bool {
+	return c.frozen.Load()
+}
+
+// frozenErr return ErrFrozen for op if the container is frozen, panicking instead when frozen strictly.
+func (c *Container) frozenErr(op string) error {
+	if !c.frozen.Load() {
+		return nil
+	}
+
+	err := errors.Errorf("%w: %s", ErrFrozen, op)
+	if c.freezePanics.Load() {
+		panic(err)
+	}
+
+	return err
+}
+
+// ignoreFrozen report whether op should be skipped because the container is frozen, logging if so.
+func (c *Container) ignoreFrozen(op string) bool {
+	err := c.frozenErr(op)
+	if err != nil {
+		c.logger.Error("ignoring config mutation", "error", err)
+	}
+
+	return err != nil
+}
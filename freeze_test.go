@@ -0,0 +1,93 @@
+package config_test
+
+import (
+	"flag"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/afero"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/phpboyscout/config"
+)
+
+func TestContainer_Freeze(t *testing.T) {
+	t.Parallel()
+	l := log.New(io.Discard)
+
+	t.Run("ignores mutation", func(t *testing.T) {
+		t.Parallel()
+		c := config.NewReaderContainer(l, "yaml", strings.NewReader(firstMockFilesYaml))
+		c.Set("yaml.key", "overridden")
+		c.Freeze()
+		assert.True(t, c.Frozen())
+
+		c.Set("yaml.key", "frozen")
+		assert.Equal(t, "overridden", c.GetString("yaml.key"))
+		assert.True(t, c.GetBool("yaml.bool"))
+
+		require.ErrorIs(t, c.Unset("yaml.key"), config.ErrFrozen)
+		assert.Equal(t, "overridden", c.GetString("yaml.key"))
+
+		c.AddObserverFunc(func(config.Containable, chan error) {})
+		assert.Empty(t, c.GetObservers())
+	})
+
+	t.Run("refuses other mutators", func(t *testing.T) {
+		t.Parallel()
+		c := config.NewReaderContainer(l, "yaml", strings.NewReader(redactMockYaml))
+		c.Set("api.token", "enc:token")
+		c.Freeze()
+
+		std := flag.NewFlagSet("test", flag.ContinueOnError)
+		std.String("database-host", "", "")
+		require.NoError(t, std.Parse([]string{"-database-host=flag-host"}))
+		c.BindStdFlags(std)
+		assert.Equal(t, "db.example.com", c.GetString("database.host"))
+
+		pflags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		pflags.String("database.host", "", "")
+		require.NoError(t, pflags.Parse([]string{"--database.host=pflag-host"}))
+		require.ErrorIs(t, c.BindPFlags(pflags), config.ErrFrozen)
+		require.ErrorIs(t, c.BindPFlag("database.host", pflags.Lookup("database.host")), config.ErrFrozen)
+		require.ErrorIs(t, c.BindEnv("database.host", "FROZEN_DB_HOST"), config.ErrFrozen)
+		assert.Equal(t, "db.example.com", c.GetString("database.host"))
+
+		c.SetKeyNormalizer(func(string) string { return "x" })
+		assert.Equal(t, "db.example.com", c.GetString("database.host"))
+
+		c.SetSecretKeys("database.host")
+		assert.Contains(t, c.ToJSON(), "db.example.com")
+
+		c.SetDecryptor(func(string) (string, error) { return "decrypted", nil })
+		assert.Equal(t, "enc:token", c.GetString("api.token"))
+		c.RegisterDeprecation("database.host", "database.address")
+		assert.Empty(t, c.GetString("database.address"))
+
+		fs := afero.NewMemMapFs()
+		c.SetFs(fs)
+		c.AddFinalizer(func(config.Containable) error { return nil })
+		require.ErrorIs(t, c.Reset(), config.ErrFrozen)
+	})
+
+	t.Run("strict mode panics", func(t *testing.T) {
+		t.Parallel()
+		c := config.NewReaderContainer(l, "yaml", strings.NewReader(firstMockFilesYaml))
+		c.FreezeStrict()
+
+		assert.PanicsWithError(t, "config container is frozen: Set yaml.key", func() {
+			c.Set("yaml.key", "frozen")
+		})
+		assert.Panics(t, func() {
+			_ = c.Unset("yaml.key")
+		})
+		assert.Panics(t, func() {
+			c.AddObserver(config.Observer{})
+		})
+		assert.Equal(t, "value", c.GetString("yaml.key"))
+	})
+}
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"net/url"
+	"reflect"
+	"sync"
+
+	"github.com/go-errors/errors"
+	"github.com/mitchellh/mapstructure"
+)
+
+// Get retrieve the value at key decoded into T.
+// Interface methods can't be generic so this is provided as a free function operating on any Containable.
+func Get[T any](c Containable, key string) (T, error) {
+	var out T
+
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		Result:           &out,
+		WeaklyTypedInput: true,
+		Squash:           true,
+		DecodeHook:       decodeHook(),
+	})
+	if err != nil {
+		return out, errors.Wrap(err, 0)
+	}
+
+	if err := decoder.Decode(c.Get(key)); err != nil {
+		return out, errors.Errorf("unable to decode config key %q: %w", key, err)
+	}
+
+	return out, nil
+}
+
+// GetMap retrieve the subtree at key decoded into a map of T, e.g. a map of service name to service config.
+// An absent subtree yields an empty map.
+func GetMap[T any](c Containable, key string) (map[string]T, error) {
+	out := make(map[string]T)
+
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		Result:           &out,
+		WeaklyTypedInput: true,
+		Squash:           true,
+		DecodeHook:       decodeHook(),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+
+	if err := decoder.Decode(c.Get(key)); err != nil {
+		return nil, errors.Errorf("unable to decode config key %q: %w", key, err)
+	}
+
+	return out, nil
+}
+
+var (
+	decodeHooksMu sync.RWMutex
+	decodeHooks   []mapstructure.DecodeHookFunc
+)
+
+// RegisterDecodeHook register a hook applied by Get, Unmarshal and UnmarshalKey, allowing custom types to be
+// decoded from config values. Registered hooks run after the built-in duration, IP, slice and URL hooks.
+func RegisterDecodeHook(hook mapstructure.DecodeHookFunc) {
+	decodeHooksMu.Lock()
+	defer decodeHooksMu.Unlock()
+
+	decodeHooks = append(decodeHooks, hook)
+}
+
+// decodeHook compose the built-in hooks with any registered ones.
+func decodeHook() mapstructure.DecodeHookFunc {
+	decodeHooksMu.RLock()
+	defer decodeHooksMu.RUnlock()
+
+	hooks := []mapstructure.DecodeHookFunc{
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToIPHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+		stringToURLHookFunc(),
+	}
+
+	return mapstructure.ComposeDecodeHookFunc(append(hooks, decodeHooks...)...)
+}
+
+// decoderOptions configure viper's decoder with the package's decode hooks, and squash embedded structs so
+// that their fields are populated from the enclosing struct's keys.
+func decoderOptions(dc *mapstructure.DecoderConfig) {
+	dc.DecodeHook = decodeHook()
+	dc.Squash = true
+}
+
+// Unmarshal decode the whole config into out, a pointer to a struct or map.
+func (c *Container) Unmarshal(out interface{}) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if err := c.viper.Unmarshal(out, decoderOptions); err != nil {
+		return errors.Errorf("unable to decode config: %w", err)
+	}
+
+	return nil
+}
+
+// UnmarshalKey decode the value at key into out, a pointer to a struct, map or value.
+func (c *Container) UnmarshalKey(key string, out interface{}) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if err := c.viper.UnmarshalKey(c.lookupKey(key), out, decoderOptions); err != nil {
+		return errors.Errorf("unable to decode config key %q: %w", key, err)
+	}
+
+	return nil
+}
+
+// stringToURLHookFunc decode strings into url.URL values.
+func stringToURLHookFunc() mapstructure.DecodeHookFuncType {
+	return func(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+		if f.Kind() != reflect.String || t != reflect.TypeOf(url.URL{}) {
+			return data, nil
+		}
+
+		u, err := url.Parse(data.(string))
+		if err != nil {
+			return nil, err
+		}
+
+		return *u, nil
+	}
+}
@@ -0,0 +1,208 @@
+package config_test
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/phpboyscout/config"
+)
+
+var genericMockYaml = `generic:
+  int: 42
+  list:
+    - one
+    - two
+  duration: 1m30s
+  url: "https://example.com:8443/path"
+  string: "not a number"`
+
+func TestGet(t *testing.T) {
+	t.Parallel()
+	l := log.New(io.Discard)
+	c := config.NewReaderContainer(l, "yaml", strings.NewReader(genericMockYaml))
+
+	t.Run("test Get[int]", func(t *testing.T) {
+		t.Parallel()
+		v, err := config.Get[int](c, "generic.int")
+		require.NoError(t, err)
+		assert.Equal(t, 42, v)
+	})
+
+	t.Run("test Get[[]string]", func(t *testing.T) {
+		t.Parallel()
+		v, err := config.Get[[]string](c, "generic.list")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"one", "two"}, v)
+	})
+
+	t.Run("test Get[time.Duration]", func(t *testing.T) {
+		t.Parallel()
+		v, err := config.Get[time.Duration](c, "generic.duration")
+		require.NoError(t, err)
+		assert.Equal(t, 90*time.Second, v)
+	})
+
+	t.Run("test Get[url.URL]", func(t *testing.T) {
+		t.Parallel()
+		v, err := config.Get[url.URL](c, "generic.url")
+		require.NoError(t, err)
+		assert.Equal(t, "example.com:8443", v.Host)
+		assert.Equal(t, "/path", v.Path)
+	})
+
+	t.Run("test decode failure", func(t *testing.T) {
+		t.Parallel()
+		v, err := config.Get[int](c, "generic.string")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "generic.string")
+		assert.Zero(t, v)
+	})
+}
+
+func TestGetMap(t *testing.T) {
+	t.Parallel()
+	type service struct {
+		Port    int           `mapstructure:"port"`
+		Timeout time.Duration `mapstructure:"timeout"`
+	}
+
+	l := log.New(io.Discard)
+	c := config.NewReaderContainer(l, "yaml", strings.NewReader(`services:
+  api:
+    port: 1
+    timeout: 5s
+  web:
+    port: 2
+broken:
+  api: "not a service"`))
+
+	t.Run("with map of structs", func(t *testing.T) {
+		t.Parallel()
+		m, err := config.GetMap[service](c, "services")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]service{
+			"api": {Port: 1, Timeout: 5 * time.Second},
+			"web": {Port: 2},
+		}, m)
+	})
+
+	t.Run("with empty subtree", func(t *testing.T) {
+		t.Parallel()
+		m, err := config.GetMap[service](c, "missing")
+		require.NoError(t, err)
+		assert.Empty(t, m)
+	})
+
+	t.Run("with decode failure", func(t *testing.T) {
+		t.Parallel()
+		_, err := config.GetMap[service](c, "broken")
+		require.Error(t, err)
+	})
+}
+
+type logLevel int
+
+const (
+	logLevelInfo logLevel = iota
+	logLevelDebug
+)
+
+func TestContainer_Unmarshal(t *testing.T) {
+	t.Parallel()
+	config.RegisterDecodeHook(func(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+		if f.Kind() != reflect.String || t != reflect.TypeOf(logLevel(0)) {
+			return data, nil
+		}
+
+		switch data.(string) {
+		case "debug":
+			return logLevelDebug, nil
+		case "info":
+			return logLevelInfo, nil
+		}
+
+		return nil, fmt.Errorf("unknown log level %q", data)
+	})
+
+	type server struct {
+		Level   logLevel      `mapstructure:"level"`
+		Timeout time.Duration `mapstructure:"timeout"`
+		Bind    net.IP        `mapstructure:"bind"`
+	}
+
+	l := log.New(io.Discard)
+	c := config.NewReaderContainer(l, "yaml", strings.NewReader(`server:
+  level: debug
+  timeout: 2s
+  bind: 10.0.0.1
+broken:
+  level: shouty`))
+
+	t.Run("with UnmarshalKey", func(t *testing.T) {
+		t.Parallel()
+		var s server
+		require.NoError(t, c.UnmarshalKey("server", &s))
+		assert.Equal(t, logLevelDebug, s.Level)
+		assert.Equal(t, 2*time.Second, s.Timeout)
+		assert.Equal(t, "10.0.0.1", s.Bind.String())
+	})
+
+	t.Run("with Unmarshal", func(t *testing.T) {
+		t.Parallel()
+		var all struct {
+			Server server `mapstructure:"server"`
+		}
+		require.NoError(t, c.Unmarshal(&all))
+		assert.Equal(t, logLevelDebug, all.Server.Level)
+	})
+
+	t.Run("with hook error", func(t *testing.T) {
+		t.Parallel()
+		var s server
+		err := c.UnmarshalKey("broken", &s)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "shouty")
+	})
+}
+
+func TestContainer_Unmarshal_Embedded(t *testing.T) {
+	t.Parallel()
+	type baseConfig struct {
+		Name    string `mapstructure:"name"`
+		Version string `mapstructure:"version"`
+	}
+	type appConfig struct {
+		baseConfig
+		Port int `mapstructure:"port"`
+	}
+
+	l := log.New(io.Discard)
+	c := config.NewReaderContainer(l, "yaml", strings.NewReader(`app:
+  name: api
+  version: "1.2"
+  port: 8080`))
+
+	var viaKey appConfig
+	require.NoError(t, c.UnmarshalKey("app", &viaKey))
+	assert.Equal(t, appConfig{baseConfig: baseConfig{Name: "api", Version: "1.2"}, Port: 8080}, viaKey)
+
+	viaGet, err := config.Get[appConfig](c, "app")
+	require.NoError(t, err)
+	assert.Equal(t, viaKey, viaGet)
+
+	var all struct {
+		App appConfig `mapstructure:"app"`
+	}
+	require.NoError(t, c.Unmarshal(&all))
+	assert.Equal(t, viaKey, all.App)
+}
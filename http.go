@@ -0,0 +1,89 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/go-errors/errors"
+)
+
+// defaultHTTPTimeout applied to requests made by LoadHTTP when no client is supplied.
+const defaultHTTPTimeout = 30 * time.Second
+
+// LoadHTTP Initialise a configuration container from config served over HTTP.
+// Each URL is fetched in order and merged, later responses taking precedence. A nil client uses a
+// default client with a sane timeout. A response that fails to parse is an error, as with LoadRemote.
+func LoadHTTP(urls []string, format string, logger *log.Logger, client *http.Client) (Containable, error) {
+	return LoadHTTPContext(context.Background(), urls, format, logger, client)
+}
+
+// LoadHTTPContext Initialise a configuration container from config served over HTTP as LoadHTTP, aborting
+// outstanding requests when ctx is cancelled or its deadline passes.
+func LoadHTTPContext(ctx context.Context, urls []string, format string, logger *log.Logger, client *http.Client) (Containable, error) {
+	if client == nil {
+		client = &http.Client{Timeout: defaultHTTPTimeout}
+	}
+
+	readers := make([]io.Reader, 0, len(urls))
+
+	for _, u := range urls {
+		b, err := fetchHTTP(ctx, client, u, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		readers = append(readers, bytes.NewReader(b))
+	}
+
+	c := NewReaderContainer(logger, format, readers...)
+	if c.HasLoadErrors() {
+		return nil, errors.Errorf("unable to parse config from %s: %w", strings.Join(urls, ", "), errors.Join(c.LoadErrors()...))
+	}
+
+	return c, nil
+}
+
+// fetchHTTP GET the body of u with any extra headers, erroring on any non-200 response.
+func fetchHTTP(ctx context.Context, client *http.Client, u string, header http.Header) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, errors.WrapPrefix(err, "invalid config url "+u, 0)
+	}
+
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	return doHTTP(client, req)
+}
+
+// doHTTP send req, returning the response body and erroring on any non-200 response.
+func doHTTP(client *http.Client, req *http.Request) ([]byte, error) {
+	u := req.URL.String()
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctxErr := req.Context().Err(); ctxErr != nil {
+			return nil, errors.WrapPrefix(ctxErr, "unable to fetch config from "+u, 0)
+		}
+
+		return nil, errors.WrapPrefix(err, "unable to fetch config from "+u, 0)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unable to fetch config from %s: unexpected status %s", u, resp.Status)
+	}
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.WrapPrefix(err, "unable to read config from "+u, 0)
+	}
+
+	return b, nil
+}
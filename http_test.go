@@ -0,0 +1,102 @@
+package config_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/phpboyscout/config"
+)
+
+func newConfigServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/first.yml", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, firstMockFilesYaml)
+	})
+	mux.HandleFunc("/second.yml", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, secondMockFilesYaml)
+	})
+	mux.HandleFunc("/broken.yml", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "yaml: [unclosed")
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestLoadHTTP(t *testing.T) {
+	t.Parallel()
+	logger := log.New(io.Discard)
+	srv := newConfigServer(t)
+
+	t.Run("with single endpoint", func(t *testing.T) {
+		t.Parallel()
+		c, err := config.LoadHTTP([]string{srv.URL + "/first.yml"}, "yaml", logger, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "value", c.GetString("yaml.key"))
+	})
+
+	t.Run("with non-200 status", func(t *testing.T) {
+		t.Parallel()
+		_, err := config.LoadHTTP([]string{srv.URL + "/missing.yml"}, "yaml", logger, srv.Client())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "404")
+	})
+
+	t.Run("with a malformed body", func(t *testing.T) {
+		t.Parallel()
+		_, err := config.LoadHTTP([]string{srv.URL + "/first.yml", srv.URL + "/broken.yml"}, "yaml", logger, srv.Client())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "broken.yml")
+	})
+
+	t.Run("with multiple endpoints merged", func(t *testing.T) {
+		t.Parallel()
+		c, err := config.LoadHTTP([]string{srv.URL + "/first.yml", srv.URL + "/second.yml"}, "yaml", logger, srv.Client())
+		require.NoError(t, err)
+		assert.Equal(t, "value2", c.GetString("yaml.key"))
+		assert.Equal(t, "secondfile", c.GetString("yaml.more.key2"))
+		assert.True(t, c.GetBool("yaml.bool"))
+	})
+}
+
+func TestLoadHTTPContext(t *testing.T) {
+	t.Parallel()
+	logger := log.New(io.Discard)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	t.Cleanup(srv.Close)
+
+	t.Run("with cancelled context", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(50*time.Millisecond, cancel)
+
+		start := time.Now()
+		_, err := config.LoadHTTPContext(ctx, []string{srv.URL + "/slow.yml"}, "yaml", logger, nil)
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("with deadline exceeded", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err := config.LoadHTTPContext(ctx, []string{srv.URL + "/slow.yml"}, "yaml", logger, srv.Client())
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
@@ -0,0 +1,149 @@
+package config
+
+import (
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/go-errors/errors"
+)
+
+// ErrUnresolvedReference returned by strict expansion when a reference can't be resolved or is cyclic.
+var ErrUnresolvedReference = errors.New("unresolved config reference")
+
+// expansionPattern matches "${...}" references in config values.
+var expansionPattern = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// configRefPattern matches cross-references to other config keys such as "${config:database.host}".
+var configRefPattern = regexp.MustCompile(`\$\{config:([^}]+)\}`)
+
+// GetStringMapString get map of strings from config with any "${config:other.key}" references in the
+// values resolved against the rest of the config. A reference to a key that is not set is an error.
+func (c *Container) GetStringMapString(key string) (map[string]string, error) {
+	m, err := c.decryptedStringMap(key)
+	if err != nil {
+		return nil, err
+	}
+
+	// references are resolved without the lock held, as requireString takes it for each one.
+	for k, v := range m {
+		resolved, err := c.resolveConfigRefs(v)
+		if err != nil {
+			return nil, errors.Errorf("unable to resolve %s.%s: %w", key, k, err)
+		}
+		m[k] = resolved
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return logged(c, key, m), nil
+}
+
+// decryptedStringMap get the map of strings at key with each value decrypted.
+func (c *Container) decryptedStringMap(key string) (map[string]string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	m := c.viper.GetStringMapString(c.lookupKey(key))
+	for k, v := range m {
+		plain, err := c.decrypt(v)
+		if err != nil {
+			return nil, errors.Errorf("unable to decrypt %s.%s: %w", key, k, err)
+		}
+		m[k] = plain
+	}
+
+	return m, nil
+}
+
+// resolveConfigRefs replace each "${config:...}" reference in s with the referenced string value.
+func (c *Container) resolveConfigRefs(s string) (string, error) {
+	var err error
+
+	resolved := configRefPattern.ReplaceAllStringFunc(s, func(ref string) string {
+		ref = configRefPattern.FindStringSubmatch(ref)[1]
+		v, e := c.requireString(ref)
+		if e != nil && err == nil {
+			err = e
+		}
+
+		return v
+	})
+
+	return resolved, err
+}
+
+// GetStringExpanded get string value from config with "${...}" references expanded, first against other
+// config keys and then against environment variables. Expanded values are themselves expanded; references
+// that can't be resolved, or that refer back to themselves, are left intact.
+func (c *Container) GetStringExpanded(key string) string {
+	s, _ := c.expand(key, false)
+
+	return s
+}
+
+// GetStringExpandedStrict behave like GetStringExpanded but return ErrUnresolvedReference for references
+// that can't be resolved or are cyclic.
+func (c *Container) GetStringExpandedStrict(key string) (string, error) {
+	return c.expand(key, true)
+}
+
+func (c *Container) expand(key string, strict bool) (string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	s, err := c.decrypt(c.viper.GetString(c.lookupKey(key)))
+	if err != nil {
+		return "", errors.Errorf("unable to decrypt %s: %w", key, err)
+	}
+
+	s = c.expandValue(s, map[string]bool{key: true}, func(e error) {
+		if strict && err == nil {
+			err = e
+		}
+	})
+	if err != nil {
+		return "", errors.Errorf("unable to expand %s: %w", key, err)
+	}
+
+	return logged(c, key, s), nil
+}
+
+// expandValue expand references within s, tracking the keys currently being expanded in visiting to guard
+// against cycles. Unresolvable references are reported to fail and left intact.
+func (c *Container) expandValue(s string, visiting map[string]bool, fail func(error)) string {
+	return expansionPattern.ReplaceAllStringFunc(s, func(token string) string {
+		name := expansionPattern.FindStringSubmatch(token)[1]
+		key := strings.TrimPrefix(name, "config:")
+
+		if visiting[key] {
+			fail(errors.Errorf("%w: cycle through %s", ErrUnresolvedReference, key))
+
+			return token
+		}
+
+		if c.viper.IsSet(key) {
+			plain, err := c.decrypt(c.viper.GetString(key))
+			if err != nil {
+				fail(errors.Errorf("unable to decrypt %s: %w", key, err))
+
+				return token
+			}
+
+			visiting[key] = true
+			v := c.expandValue(plain, visiting, fail)
+			delete(visiting, key)
+
+			return v
+		}
+
+		if v, ok := os.LookupEnv(name); ok && name == key {
+			return v
+		}
+
+		fail(errors.Errorf("%w: %s", ErrUnresolvedReference, name))
+
+		return token
+	})
+}
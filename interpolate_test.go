@@ -0,0 +1,92 @@
+package config_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/phpboyscout/config"
+)
+
+var interpolateMockYaml = `database:
+  host: "db.example.com"
+  port: 5432
+endpoints:
+  primary: "postgres://${config:database.host}:${config:database.port}/app"
+  static: "https://static.example.com"
+broken:
+  ref: "${config:database.missing}"`
+
+func TestContainer_GetStringMapString(t *testing.T) {
+	t.Parallel()
+	l := log.New(io.Discard)
+	c := config.NewReaderContainer(l, "yaml", strings.NewReader(interpolateMockYaml))
+
+	t.Run("with config references", func(t *testing.T) {
+		t.Parallel()
+		m, err := c.GetStringMapString("endpoints")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"primary": "postgres://db.example.com:5432/app",
+			"static":  "https://static.example.com",
+		}, m)
+	})
+
+	t.Run("with missing reference", func(t *testing.T) {
+		t.Parallel()
+		_, err := c.GetStringMapString("broken")
+		require.ErrorIs(t, err, config.ErrKeyNotSet)
+		assert.Contains(t, err.Error(), "database.missing")
+	})
+}
+
+var expandMockYaml = `server:
+  host: "localhost"
+  port: 8080
+  url: "http://${server.host}:${server.port}"
+  home: "${CONFIG_TEST_EXPAND_HOME}/app"
+  nested: "${server.url}/api"
+  unmatched: "${does.not.exist}/path"
+cycle:
+  a: "${cycle.b}"
+  b: "${cycle.a}"
+  self: "x${cycle.self}"`
+
+func TestContainer_GetStringExpanded(t *testing.T) {
+	t.Setenv("CONFIG_TEST_EXPAND_HOME", "/home/test")
+
+	l := log.New(io.Discard)
+	c := config.NewReaderContainer(l, "yaml", strings.NewReader(expandMockYaml))
+
+	t.Run("with key references", func(t *testing.T) {
+		assert.Equal(t, "http://localhost:8080", c.GetStringExpanded("server.url"))
+	})
+
+	t.Run("with env references", func(t *testing.T) {
+		assert.Equal(t, "/home/test/app", c.GetStringExpanded("server.home"))
+	})
+
+	t.Run("with nested expansion", func(t *testing.T) {
+		assert.Equal(t, "http://localhost:8080/api", c.GetStringExpanded("server.nested"))
+	})
+
+	t.Run("with unmatched reference", func(t *testing.T) {
+		assert.Equal(t, "${does.not.exist}/path", c.GetStringExpanded("server.unmatched"))
+
+		_, err := c.GetStringExpandedStrict("server.unmatched")
+		require.ErrorIs(t, err, config.ErrUnresolvedReference)
+	})
+
+	t.Run("with cycles", func(t *testing.T) {
+		assert.Equal(t, "${cycle.b}", c.GetStringExpanded("cycle.b"))
+		assert.Equal(t, "x${cycle.self}", c.GetStringExpanded("cycle.self"))
+
+		_, err := c.GetStringExpandedStrict("cycle.a")
+		require.ErrorIs(t, err, config.ErrUnresolvedReference)
+		assert.Contains(t, err.Error(), "cycle")
+	})
+}
@@ -0,0 +1,519 @@
+package config
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/go-errors/errors"
+	"github.com/spf13/afero"
+	"github.com/spf13/viper"
+)
+
+// ErrNoFilesFound returned when none of the requested config files exist and empty config is not allowed.
+var ErrNoFilesFound = errors.New("no config files found")
+
+// EmbeddedFileReader minimal interface satisfied by embed.FS, allowing embedded config to be mocked.
+type EmbeddedFileReader interface {
+	ReadFile(name string) ([]byte, error)
+}
+
+// EmbeddedFileGlobber embedded filesystem that can also be walked for glob matching, satisfied by embed.FS.
+type EmbeddedFileGlobber interface {
+	EmbeddedFileReader
+	fs.FS
+}
+
+// LoadOptions optional behaviour for LoadWithOptions.
+type LoadOptions struct {
+	// AllowEmpty return an empty container rather than ErrNoFilesFound when none of the files exist.
+	AllowEmpty bool
+	// EnvPrefix prefix applied to environment variable names, e.g. "APP" maps "db.host" to APP_DB_HOST.
+	EnvPrefix string
+	// Defaults values used for keys not provided by any other source.
+	Defaults map[string]interface{}
+	// Format config format used for every file regardless of its extension.
+	Format string
+	// StrictDuplicateKeys fail with ErrDuplicateKey when a YAML file defines the same key twice in one
+	// mapping, rather than only logging the parse failure.
+	StrictDuplicateKeys bool
+}
+
+// Load Initialise a configuration container from whichever of the given paths exist on the FS.
+// Missing files are skipped; if none exist ErrNoFilesFound is returned unless allowEmptyConfig is set.
+func Load(paths []string, fs afero.Fs, logger *log.Logger, allowEmptyConfig bool) (Containable, error) {
+	return LoadWithOptions(paths, fs, logger, LoadOptions{AllowEmpty: allowEmptyConfig})
+}
+
+// MustLoad Initialise a configuration container as Load, panicking if it fails.
+func MustLoad(paths []string, fs afero.Fs, logger *log.Logger, allowEmptyConfig bool) Containable {
+	c, err := Load(paths, fs, logger, allowEmptyConfig)
+	if err != nil {
+		panic(errors.WrapPrefix(err, fmt.Sprintf("unable to load config from %v", paths), 0))
+	}
+
+	return c
+}
+
+// LoadWithOptions Initialise a configuration container from whichever of the given paths exist on the FS,
+// as Load, with additional behaviour controlled by opts.
+func LoadWithOptions(paths []string, fs afero.Fs, logger *log.Logger, opts LoadOptions) (Containable, error) {
+	found := make([]string, 0, len(paths))
+
+	for _, p := range paths {
+		exists, err := afero.Exists(fs, p)
+		if err != nil {
+			return nil, errors.Wrap(err, 0)
+		}
+
+		if !exists {
+			logger.Debug("config file not found, skipping", "path", p)
+
+			continue
+		}
+
+		found = append(found, p)
+	}
+
+	if len(found) == 0 && !opts.AllowEmpty {
+		return nil, errors.Errorf("%w: %v", ErrNoFilesFound, paths)
+	}
+
+	if opts.StrictDuplicateKeys {
+		for _, p := range found {
+			if err := checkDuplicateKeys(fs, p, opts.Format); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	c := initContainer(logger, fs)
+	if opts.EnvPrefix != "" {
+		c.envPrefix = opts.EnvPrefix
+		c.viper.SetEnvPrefix(opts.EnvPrefix)
+	}
+
+	if opts.Format != "" {
+		c.format = opts.Format
+		c.viper.SetConfigType(opts.Format)
+	}
+
+	for k, v := range opts.Defaults {
+		c.viper.SetDefault(k, v)
+	}
+
+	c.readFiles(found...)
+	if len(found) < len(paths) {
+		c.watchForCreation(paths)
+	}
+
+	return c, nil
+}
+
+// LoadByName Initialise a configuration container from the first file called name, with any supported
+// extension, found in searchPaths, which are tried in order. ErrNoFilesFound is returned if none matches,
+// unless allowEmptyConfig is set.
+func LoadByName(name string, searchPaths []string, fs afero.Fs, logger *log.Logger, allowEmptyConfig bool) (Containable, error) {
+	v := viper.New()
+	v.SetFs(fs)
+	v.SetConfigName(name)
+	for _, p := range searchPaths {
+		v.AddConfigPath(p)
+	}
+
+	var notFound viper.ConfigFileNotFoundError
+	if err := v.ReadInConfig(); errors.As(err, &notFound) {
+		if !allowEmptyConfig {
+			return nil, errors.Errorf("%w: %s in %v", ErrNoFilesFound, name, searchPaths)
+		}
+
+		return Load(nil, fs, logger, true)
+	}
+
+	// parse failures are left for Load to record, as they would be for an explicit path.
+	return Load([]string{v.ConfigFileUsed()}, fs, logger, allowEmptyConfig)
+}
+
+// dirExtensions config file extensions recognised when loading a directory.
+var dirExtensions = map[string]bool{
+	".yaml":       true,
+	".yml":        true,
+	".json":       true,
+	".toml":       true,
+	".ini":        true,
+	".properties": true,
+}
+
+// LoadDir Initialise a configuration container from every recognised config file directly within dir,
+// merged in lexical order so that later files take precedence.
+func LoadDir(dir string, fs afero.Fs, logger *log.Logger, allowEmptyConfig bool) (Containable, error) {
+	return loadDir(dir, fs, logger, allowEmptyConfig, false)
+}
+
+// LoadDirRecursive Initialise a configuration container from every recognised config file within dir and
+// its subdirectories, merged in lexical path order so that later files take precedence.
+func LoadDirRecursive(dir string, fs afero.Fs, logger *log.Logger, allowEmptyConfig bool) (Containable, error) {
+	return loadDir(dir, fs, logger, allowEmptyConfig, true)
+}
+
+func loadDir(dir string, fs afero.Fs, logger *log.Logger, allowEmptyConfig bool, recursive bool) (Containable, error) {
+	paths := make([]string, 0)
+
+	err := afero.Walk(fs, dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			if path != dir && !recursive {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if dirExtensions[strings.ToLower(filepath.Ext(path))] {
+			paths = append(paths, path)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WrapPrefix(err, "unable to read config directory "+dir, 0)
+	}
+
+	sort.Strings(paths)
+
+	return Load(paths, fs, logger, allowEmptyConfig)
+}
+
+// FileSpec config file path along with whether it must exist.
+type FileSpec struct {
+	Path     string
+	Required bool
+}
+
+// LoadSpec Initialise a configuration container from an ordered list of file specs, later files taking
+// precedence. Missing required files are an error; missing optional files are skipped, and watched so that
+// they are merged in if created later.
+func LoadSpec(specs []FileSpec, fs afero.Fs, logger *log.Logger) (Containable, error) {
+	paths := make([]string, 0, len(specs))
+	found := make([]string, 0, len(specs))
+
+	for _, spec := range specs {
+		paths = append(paths, spec.Path)
+
+		exists, err := afero.Exists(fs, spec.Path)
+		if err != nil {
+			return nil, errors.Wrap(err, 0)
+		}
+
+		if !exists {
+			if spec.Required {
+				return nil, errors.Errorf("required config file %s not found", spec.Path)
+			}
+
+			logger.Debug("optional config file not found, skipping", "path", spec.Path)
+
+			continue
+		}
+
+		found = append(found, spec.Path)
+	}
+
+	c := NewFilesContainer(logger, fs, found...)
+	if len(found) < len(paths) {
+		c.watchForCreation(paths)
+	}
+
+	return c, nil
+}
+
+// LoadProfile Initialise a configuration container from config.yaml in baseDir, overlaid by
+// config.<profile>.yaml when present. The base file is required; the profile file is optional.
+func LoadProfile(baseDir, profile string, fs afero.Fs, logger *log.Logger) (Containable, error) {
+	return LoadSpec([]FileSpec{
+		{Path: filepath.Join(baseDir, "config.yaml"), Required: true},
+		{Path: filepath.Join(baseDir, fmt.Sprintf("config.%s.yaml", profile))},
+	}, fs, logger)
+}
+
+// LoadLayered Initialise a configuration container from baseName in dir overlaid by its environment layers,
+// e.g. config.yaml, then config.<env>.yaml, then config.<env>.local.yaml, later layers winning. The base file
+// is required; the others are skipped when absent, the .local layer being meant for uncommitted developer
+// overrides. baseName defaults to a .yaml extension when it has none, and an empty env loads the base alone.
+func LoadLayered(dir, baseName, env string, fs afero.Fs, logger *log.Logger) (Containable, error) {
+	ext := filepath.Ext(baseName)
+	if ext == "" {
+		ext = ".yaml"
+	}
+	stem := strings.TrimSuffix(baseName, filepath.Ext(baseName))
+
+	specs := []FileSpec{{Path: filepath.Join(dir, stem+ext), Required: true}}
+	if env != "" {
+		specs = append(specs,
+			FileSpec{Path: filepath.Join(dir, fmt.Sprintf("%s.%s%s", stem, env, ext))},
+			FileSpec{Path: filepath.Join(dir, fmt.Sprintf("%s.%s.local%s", stem, env, ext))},
+		)
+	}
+
+	return LoadSpec(specs, fs, logger)
+}
+
+// LoadXDG Initialise a configuration container from fileName in appName's directory under the XDG config
+// home: $XDG_CONFIG_HOME, or ~/.config when that is unset or not absolute. A missing file yields an empty
+// container, still watched so that the file is picked up if created later.
+func LoadXDG(appName, fileName string, fs afero.Fs, logger *log.Logger) (Containable, error) {
+	dir, err := xdgConfigHome()
+	if err != nil {
+		return nil, err
+	}
+
+	return Load([]string{filepath.Join(dir, appName, fileName)}, fs, logger, true)
+}
+
+// xdgConfigHome resolve the XDG base directory for user config.
+func xdgConfigHome() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); filepath.IsAbs(dir) {
+		return dir, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", errors.WrapPrefix(err, "unable to locate XDG config home", 0)
+	}
+
+	return filepath.Join(home, ".config"), nil
+}
+
+// LoadEmbed Initialise a configuration container from YAML files held in an embedded filesystem.
+// Gzip-compressed files are detected and decompressed transparently.
+func LoadEmbed(paths []string, embed EmbeddedFileReader, logger *log.Logger) (Containable, error) {
+	return loadEmbed(paths, embed, logger, false)
+}
+
+// MustLoadEmbed Initialise a configuration container as LoadEmbed, panicking if it fails.
+func MustLoadEmbed(paths []string, embed EmbeddedFileReader, logger *log.Logger) Containable {
+	c, err := LoadEmbed(paths, embed, logger)
+	if err != nil {
+		panic(errors.WrapPrefix(err, fmt.Sprintf("unable to load embedded config from %v", paths), 0))
+	}
+
+	return c
+}
+
+// LoadEmbedAll Initialise a configuration container from YAML files held in an embedded filesystem as
+// LoadEmbed, but rather than stopping at the first unreadable file, report every failure joined together.
+func LoadEmbedAll(paths []string, embed EmbeddedFileReader, logger *log.Logger) (Containable, error) {
+	var errs []error
+
+	for _, p := range paths {
+		b, err := embed.ReadFile(p)
+		if err == nil {
+			_, err = gunzipIfCompressed(b)
+		}
+
+		if err != nil {
+			errs = append(errs, errors.WrapPrefix(err, "unable to read embedded config "+p, 0))
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	return LoadEmbed(paths, embed, logger)
+}
+
+// LoadEmbedLenient Initialise a configuration container from YAML files held in an embedded filesystem,
+// skipping any that do not exist in the same way Load tolerates missing files. Other read failures are
+// still returned as errors.
+func LoadEmbedLenient(paths []string, embed EmbeddedFileReader, logger *log.Logger) (Containable, error) {
+	return loadEmbed(paths, embed, logger, true)
+}
+
+func loadEmbed(paths []string, embed EmbeddedFileReader, logger *log.Logger, allowMissing bool) (Containable, error) {
+	readers := make([]io.Reader, 0, len(paths))
+	skipped := make([]string, 0)
+
+	for i, p := range paths {
+		b, err := embed.ReadFile(p)
+		if allowMissing && errors.Is(err, fs.ErrNotExist) {
+			skipped = append(skipped, p)
+
+			continue
+		}
+
+		if err != nil {
+			return nil, errors.WrapPrefix(err, fmt.Sprintf("unable to read embedded config %s (%d of %d files read)", p, i, len(paths)), 0)
+		}
+
+		b, err = gunzipIfCompressed(b)
+		if err != nil {
+			return nil, errors.WrapPrefix(err, "unable to decompress embedded config "+p, 0)
+		}
+
+		b, err = reencodeRegistered(p, b)
+		if err != nil {
+			return nil, err
+		}
+
+		readers = append(readers, bytes.NewReader(b))
+	}
+
+	if len(skipped) > 0 {
+		logger.Warn("skipped missing embedded config files", "paths", skipped)
+	}
+
+	return NewReaderContainer(logger, "yaml", readers...), nil
+}
+
+// gunzipIfCompressed decompress b if it starts with the gzip magic bytes, otherwise return it unchanged.
+func gunzipIfCompressed(b []byte) ([]byte, error) {
+	if len(b) < 2 || b[0] != 0x1f || b[1] != 0x8b {
+		return b, nil
+	}
+
+	r, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return io.ReadAll(r)
+}
+
+// LoadEmbedGlob Initialise a configuration container from every embedded YAML file matching pattern,
+// merged in sorted order. ErrNoFilesFound is returned if the pattern matches nothing.
+func LoadEmbedGlob(pattern string, embed EmbeddedFileGlobber, logger *log.Logger) (Containable, error) {
+	matches, err := fs.Glob(embed, pattern)
+	if err != nil {
+		return nil, errors.WrapPrefix(err, "invalid embedded config pattern "+pattern, 0)
+	}
+
+	if len(matches) == 0 {
+		return nil, errors.Errorf("%w: %s", ErrNoFilesFound, pattern)
+	}
+
+	sort.Strings(matches)
+
+	return LoadEmbed(matches, embed, logger)
+}
+
+// LoadWithFallback Initialise a configuration container from the given files, falling back to embedded
+// defaults when none of the files exist. Environment variables overlay whichever source is used.
+func LoadWithFallback(paths []string, fs afero.Fs, embed EmbeddedFileReader, embedPaths []string, logger *log.Logger) (Containable, error) {
+	c, err := Load(paths, fs, logger, false)
+	if err == nil {
+		return c, nil
+	}
+
+	if !errors.Is(err, ErrNoFilesFound) {
+		return nil, err
+	}
+
+	logger.Info("no config files found, falling back to embedded defaults", "paths", paths)
+
+	return LoadEmbed(embedPaths, embed, logger)
+}
+
+// LoadFS Initialise a configuration container from files in any fs.FS, such as testing/fstest or a zip
+// archive. Each file's format is inferred from its extension and files are merged in order.
+func LoadFS(paths []string, fsys fs.FS, logger *log.Logger) (Containable, error) {
+	c := initContainer(logger, afero.NewOsFs())
+	c.ID = strings.Join(paths, ";")
+
+	for _, p := range paths {
+		format, err := formatFromPath(p)
+		if err != nil {
+			return nil, err
+		}
+
+		b, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return nil, errors.WrapPrefix(err, "unable to read config "+p, 0)
+		}
+
+		c.viper.SetConfigType(format)
+		if err := c.viper.MergeConfig(bytes.NewReader(b)); err != nil {
+			return nil, errors.WrapPrefix(err, "unable to parse config "+p, 0)
+		}
+	}
+
+	return c, nil
+}
+
+// LoadStdin merge config piped on stdin over base, stdin taking precedence. When stdin is a terminal or
+// empty, base is returned unchanged. A nil base starts from an empty container.
+func LoadStdin(format string, logger *log.Logger, base Containable) (Containable, error) {
+	if info, err := os.Stdin.Stat(); err != nil || info.Mode()&os.ModeCharDevice != 0 {
+		return LoadReader(nil, format, logger, base)
+	}
+
+	return LoadReader(os.Stdin, format, logger, base)
+}
+
+// LoadReader merge config read from r over base as LoadStdin does. A nil or empty r returns base unchanged.
+func LoadReader(r io.Reader, format string, logger *log.Logger, base Containable) (Containable, error) {
+	var b []byte
+	if r != nil {
+		var err error
+		if b, err = io.ReadAll(r); err != nil {
+			return nil, errors.WrapPrefix(err, "unable to read config", 0)
+		}
+	}
+
+	if len(bytes.TrimSpace(b)) == 0 {
+		if base == nil {
+			return NewReaderContainer(logger, format), nil
+		}
+
+		return base, nil
+	}
+
+	c := initContainer(logger, afero.NewOsFs())
+	c.ID = "stdin"
+	if base != nil {
+		if err := c.viper.MergeConfigMap(base.GetViper().AllSettings()); err != nil {
+			return nil, errors.Wrap(err, 0)
+		}
+	}
+
+	c.viper.SetConfigType(format)
+	if err := c.viper.MergeConfig(bytes.NewReader(b)); err != nil {
+		return nil, errors.WrapPrefix(err, "unable to parse config", 0)
+	}
+
+	return c, nil
+}
+
+// LoadZip Initialise a configuration container from entries of a zip archive, merged in order with each
+// entry's format inferred from its extension.
+func LoadZip(archive io.ReaderAt, size int64, paths []string, logger *log.Logger) (Containable, error) {
+	r, err := zip.NewReader(archive, size)
+	if err != nil {
+		return nil, errors.WrapPrefix(err, "unable to open config archive", 0)
+	}
+
+	return LoadFS(paths, r, logger)
+}
+
+// formatFromPath infer the config format from the extension of p.
+func formatFromPath(p string) (string, error) {
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(p), "."))
+	for _, supported := range viper.SupportedExts {
+		if ext == supported {
+			return ext, nil
+		}
+	}
+
+	return "", errors.Errorf("unsupported config format for %s", p)
+}
@@ -0,0 +1,659 @@
+package config_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/phpboyscout/config"
+)
+
+var embeddedMockYaml = `fallback:
+  key: "embedded"
+  other: "embedded-other"`
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+	logger := log.New(io.Discard)
+
+	t.Run("with existing and missing files", func(t *testing.T) {
+		t.Parallel()
+		fs := afero.NewMemMapFs()
+		err := afero.WriteFile(fs, "first.yml", []byte(firstMockFilesYaml), 0o644)
+		require.NoError(t, err)
+
+		c, err := config.Load([]string{"missing.yml", "first.yml"}, fs, logger, false)
+		require.NoError(t, err)
+		assert.Equal(t, "value", c.GetString("yaml.key"))
+	})
+
+	t.Run("with no files found", func(t *testing.T) {
+		t.Parallel()
+		_, err := config.Load([]string{"missing.yml"}, afero.NewMemMapFs(), logger, false)
+		require.ErrorIs(t, err, config.ErrNoFilesFound)
+	})
+
+	t.Run("with no files found and empty allowed", func(t *testing.T) {
+		t.Parallel()
+		c, err := config.Load([]string{"missing.yml"}, afero.NewMemMapFs(), logger, true)
+		require.NoError(t, err)
+		assert.Empty(t, c.GetString("yaml.key"))
+	})
+
+	t.Run("with ini and properties files", func(t *testing.T) {
+		t.Parallel()
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, "app.ini", []byte("[database]\nhost = ini.local\nport = 5432"), 0o644))
+		require.NoError(t, afero.WriteFile(fs, "app.properties", []byte("database.host=props.local"), 0o644))
+
+		c, err := config.Load([]string{"app.ini"}, fs, logger, false)
+		require.NoError(t, err)
+		assert.Equal(t, "ini.local", c.GetString("database.host"))
+		assert.Equal(t, 5432, c.GetInt("database.port"))
+
+		c, err = config.Load([]string{"app.ini", "app.properties"}, fs, logger, false)
+		require.NoError(t, err)
+		assert.Equal(t, "props.local", c.GetString("database.host"))
+		assert.Equal(t, 5432, c.GetInt("database.port"))
+	})
+}
+
+func TestLoadByName(t *testing.T) {
+	t.Parallel()
+	logger := log.New(io.Discard)
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/opt/app/settings.toml", []byte("[server]\nport = 9000"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/etc/app/other.yaml", []byte("server:\n  port: 1"), 0o644))
+
+	t.Run("with the file in the second search path", func(t *testing.T) {
+		t.Parallel()
+		c, err := config.LoadByName("settings", []string{"/etc/app", "/opt/app"}, fs, logger, false)
+		require.NoError(t, err)
+		assert.Equal(t, 9000, c.GetInt("server.port"))
+		assert.Equal(t, []string{"/opt/app/settings.toml"}, c.(*config.Container).ConfigFilesLoaded())
+	})
+
+	t.Run("with no match", func(t *testing.T) {
+		t.Parallel()
+		_, err := config.LoadByName("missing", []string{"/etc/app", "/opt/app"}, fs, logger, false)
+		require.ErrorIs(t, err, config.ErrNoFilesFound)
+		assert.Contains(t, err.Error(), "missing")
+	})
+
+	t.Run("with no match and empty allowed", func(t *testing.T) {
+		t.Parallel()
+		c, err := config.LoadByName("missing", []string{"/etc/app"}, fs, logger, true)
+		require.NoError(t, err)
+		assert.False(t, c.IsSet("server.port"))
+	})
+}
+
+func TestLoadLayered(t *testing.T) {
+	t.Parallel()
+	logger := log.New(io.Discard)
+
+	t.Run("with all three layers", func(t *testing.T) {
+		t.Parallel()
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, "conf/config.yaml", []byte("db:\n  host: base\n  port: 5432\n  user: app"), 0o644))
+		require.NoError(t, afero.WriteFile(fs, "conf/config.staging.yaml", []byte("db:\n  host: staging\n  port: 6432"), 0o644))
+		require.NoError(t, afero.WriteFile(fs, "conf/config.staging.local.yaml", []byte("db:\n  host: localhost"), 0o644))
+
+		c, err := config.LoadLayered("conf", "config", "staging", fs, logger)
+		require.NoError(t, err)
+		assert.Equal(t, "localhost", c.GetString("db.host"))
+		assert.Equal(t, 6432, c.GetInt("db.port"))
+		assert.Equal(t, "app", c.GetString("db.user"))
+	})
+
+	t.Run("with base and local layers only", func(t *testing.T) {
+		t.Parallel()
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, "conf/app.json", []byte(`{"db": {"host": "base", "port": 5432}}`), 0o644))
+		require.NoError(t, afero.WriteFile(fs, "conf/app.dev.local.json", []byte(`{"db": {"host": "localhost"}}`), 0o644))
+
+		c, err := config.LoadLayered("conf", "app.json", "dev", fs, logger)
+		require.NoError(t, err)
+		assert.Equal(t, "localhost", c.GetString("db.host"))
+		assert.Equal(t, 5432, c.GetInt("db.port"))
+	})
+
+	t.Run("with the base layer missing", func(t *testing.T) {
+		t.Parallel()
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, "conf/config.dev.yaml", []byte("db:\n  host: dev"), 0o644))
+
+		_, err := config.LoadLayered("conf", "config", "dev", fs, logger)
+		require.Error(t, err)
+	})
+}
+
+func TestLoadXDG(t *testing.T) {
+	logger := log.New(io.Discard)
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/xdg/mycli/config.yaml", []byte("theme: dark"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/home/tester/.config/mycli/config.yaml", []byte("theme: light"), 0o644))
+
+	t.Run("with XDG_CONFIG_HOME set", func(t *testing.T) {
+		t.Setenv("XDG_CONFIG_HOME", "/xdg")
+
+		c, err := config.LoadXDG("mycli", "config.yaml", fs, logger)
+		require.NoError(t, err)
+		assert.Equal(t, "dark", c.GetString("theme"))
+	})
+
+	t.Run("with XDG_CONFIG_HOME unset", func(t *testing.T) {
+		t.Setenv("XDG_CONFIG_HOME", "")
+		t.Setenv("HOME", "/home/tester")
+
+		c, err := config.LoadXDG("mycli", "config.yaml", fs, logger)
+		require.NoError(t, err)
+		assert.Equal(t, "light", c.GetString("theme"))
+	})
+
+	t.Run("with a relative XDG_CONFIG_HOME", func(t *testing.T) {
+		t.Setenv("XDG_CONFIG_HOME", "xdg")
+		t.Setenv("HOME", "/home/tester")
+
+		c, err := config.LoadXDG("mycli", "config.yaml", fs, logger)
+		require.NoError(t, err)
+		assert.Equal(t, "light", c.GetString("theme"))
+	})
+
+	t.Run("with no config file", func(t *testing.T) {
+		t.Setenv("XDG_CONFIG_HOME", "/xdg")
+
+		c, err := config.LoadXDG("othercli", "config.yaml", fs, logger)
+		require.NoError(t, err)
+		assert.False(t, c.IsSet("theme"))
+	})
+}
+
+func TestMustLoad(t *testing.T) {
+	t.Parallel()
+	logger := log.New(io.Discard)
+
+	t.Run("with existing file", func(t *testing.T) {
+		t.Parallel()
+		fs := afero.NewMemMapFs()
+		err := afero.WriteFile(fs, "first.yml", []byte(firstMockFilesYaml), 0o644)
+		require.NoError(t, err)
+
+		c := config.MustLoad([]string{"first.yml"}, fs, logger, false)
+		assert.Equal(t, "value", c.GetString("yaml.key"))
+	})
+
+	t.Run("with no files found", func(t *testing.T) {
+		t.Parallel()
+		fs := afero.NewMemMapFs()
+
+		defer func() {
+			r := recover()
+			require.NotNil(t, r)
+			err, ok := r.(error)
+			require.True(t, ok)
+			assert.ErrorIs(t, err, config.ErrNoFilesFound)
+			assert.Contains(t, err.Error(), "missing.yml")
+		}()
+
+		config.MustLoad([]string{"missing.yml"}, fs, logger, false)
+	})
+}
+
+func TestLoadDir(t *testing.T) {
+	t.Parallel()
+	logger := log.New(io.Discard)
+	fs := afero.NewMemMapFs()
+	files := map[string]string{
+		"conf/10-base.yaml":        "dir:\n  name: base\n  level: base\n  format: yaml",
+		"conf/20-override.json":    `{"dir": {"level": "json"}}`,
+		"conf/30-final.toml":       "[dir]\nlevel = \"toml\"",
+		"conf/40-legacy.ini":       "[dir]\nlegacy = ini",
+		"conf/README.md":           "dir:\n  level: markdown",
+		"conf/notes.txt":           "ignored",
+		"conf/nested/99-deep.yml":  "dir:\n  level: nested",
+		"conf/nested/ignored.conf": "ignored",
+	}
+	for name, content := range files {
+		require.NoError(t, afero.WriteFile(fs, name, []byte(content), 0o644))
+	}
+
+	t.Run("with mixed config and non-config files", func(t *testing.T) {
+		t.Parallel()
+		c, err := config.LoadDir("conf", fs, logger, false)
+		require.NoError(t, err)
+		assert.Equal(t, "base", c.GetString("dir.name"))
+		assert.Equal(t, "toml", c.GetString("dir.level"))
+		assert.Equal(t, "ini", c.GetString("dir.legacy"))
+	})
+
+	t.Run("with recursive option", func(t *testing.T) {
+		t.Parallel()
+		c, err := config.LoadDirRecursive("conf", fs, logger, false)
+		require.NoError(t, err)
+		assert.Equal(t, "base", c.GetString("dir.name"))
+		assert.Equal(t, "nested", c.GetString("dir.level"))
+	})
+
+	t.Run("with no recognised files", func(t *testing.T) {
+		t.Parallel()
+		empty := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(empty, "conf/notes.txt", []byte("ignored"), 0o644))
+
+		_, err := config.LoadDir("conf", empty, logger, false)
+		require.ErrorIs(t, err, config.ErrNoFilesFound)
+
+		_, err = config.LoadDir("conf", empty, logger, true)
+		require.NoError(t, err)
+	})
+}
+
+func TestLoadSpec(t *testing.T) {
+	t.Parallel()
+	logger := log.New(io.Discard)
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "first.yml", []byte(firstMockFilesYaml), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "second.yml", []byte(secondMockFilesYaml), 0o644))
+
+	t.Run("with missing optional file", func(t *testing.T) {
+		t.Parallel()
+		c, err := config.LoadSpec([]config.FileSpec{
+			{Path: "first.yml", Required: true},
+			{Path: "optional.yml"},
+			{Path: "second.yml"},
+		}, fs, logger)
+		require.NoError(t, err)
+		assert.Equal(t, "value2", c.GetString("yaml.key"))
+		assert.True(t, c.GetBool("yaml.bool"))
+	})
+
+	t.Run("with missing required file", func(t *testing.T) {
+		t.Parallel()
+		_, err := config.LoadSpec([]config.FileSpec{
+			{Path: "first.yml", Required: true},
+			{Path: "optional.yml"},
+			{Path: "required.yml", Required: true},
+		}, fs, logger)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "required.yml")
+	})
+}
+
+func TestLoadProfile(t *testing.T) {
+	t.Parallel()
+	logger := log.New(io.Discard)
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "conf/config.yaml", []byte("db:\n  host: localhost\n  port: 5432"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "conf/config.prod.yaml", []byte("db:\n  host: prod.db"), 0o644))
+
+	t.Run("with profile overlay", func(t *testing.T) {
+		t.Parallel()
+		c, err := config.LoadProfile("conf", "prod", fs, logger)
+		require.NoError(t, err)
+		assert.Equal(t, "prod.db", c.GetString("db.host"))
+		assert.Equal(t, 5432, c.GetInt("db.port"))
+	})
+
+	t.Run("with base only", func(t *testing.T) {
+		t.Parallel()
+		c, err := config.LoadProfile("conf", "dev", fs, logger)
+		require.NoError(t, err)
+		assert.Equal(t, "localhost", c.GetString("db.host"))
+	})
+
+	t.Run("with missing base", func(t *testing.T) {
+		t.Parallel()
+		_, err := config.LoadProfile("missing", "prod", fs, logger)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "config.yaml")
+	})
+}
+
+func TestLoadWithOptions(t *testing.T) {
+	logger := log.New(io.Discard)
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "first.yml", []byte(firstMockFilesYaml), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "config.conf", []byte(`{"conf": {"key": "json"}}`), 0o644))
+
+	t.Run("with AllowEmpty", func(t *testing.T) {
+		_, err := config.LoadWithOptions([]string{"missing.yml"}, fs, logger, config.LoadOptions{})
+		require.ErrorIs(t, err, config.ErrNoFilesFound)
+
+		c, err := config.LoadWithOptions([]string{"missing.yml"}, fs, logger, config.LoadOptions{AllowEmpty: true})
+		require.NoError(t, err)
+		assert.Empty(t, c.GetString("yaml.key"))
+	})
+
+	t.Run("with EnvPrefix", func(t *testing.T) {
+		t.Setenv("YAML_KEY", "unprefixed")
+		t.Setenv("MYAPP_YAML_KEY", "prefixed")
+
+		c, err := config.LoadWithOptions([]string{"first.yml"}, fs, logger, config.LoadOptions{EnvPrefix: "myapp"})
+		require.NoError(t, err)
+		assert.Equal(t, "prefixed", c.GetString("yaml.key"))
+	})
+
+	t.Run("with Defaults", func(t *testing.T) {
+		c, err := config.LoadWithOptions([]string{"first.yml"}, fs, logger, config.LoadOptions{
+			Defaults: map[string]interface{}{"yaml.key": "default", "yaml.extra": "default-extra"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "value", c.GetString("yaml.key"))
+		assert.Equal(t, "default-extra", c.GetString("yaml.extra"))
+	})
+
+	t.Run("with Format", func(t *testing.T) {
+		c, err := config.LoadWithOptions([]string{"config.conf"}, fs, logger, config.LoadOptions{Format: "json"})
+		require.NoError(t, err)
+		assert.Equal(t, "json", c.GetString("conf.key"))
+	})
+
+	t.Run("with StrictDuplicateKeys", func(t *testing.T) {
+		require.NoError(t, afero.WriteFile(fs, "dup.yml", []byte("db:\n  host: a\n  port: 1\n  host: b\n"), 0o644))
+		opts := config.LoadOptions{StrictDuplicateKeys: true}
+
+		_, err := config.LoadWithOptions([]string{"first.yml", "dup.yml"}, fs, logger, opts)
+		require.ErrorIs(t, err, config.ErrDuplicateKey)
+		assert.Contains(t, err.Error(), "dup.yml")
+		assert.Contains(t, err.Error(), `"db.host" at line 4`)
+
+		c, err := config.LoadWithOptions([]string{"first.yml"}, fs, logger, opts)
+		require.NoError(t, err)
+		assert.Equal(t, "value", c.GetString("yaml.key"))
+	})
+}
+
+func TestLoadEmbed(t *testing.T) {
+	t.Parallel()
+	logger := log.New(io.Discard)
+	embed := fstest.MapFS{
+		"config/first.yml":  {Data: []byte(firstMockFilesYaml)},
+		"config/second.yml": {Data: []byte(secondMockFilesYaml)},
+	}
+
+	t.Run("with multiple embedded files", func(t *testing.T) {
+		t.Parallel()
+		c, err := config.LoadEmbed([]string{"config/first.yml", "config/second.yml"}, embed, logger)
+		require.NoError(t, err)
+		assert.Equal(t, "value2", c.GetString("yaml.key"))
+		assert.True(t, c.GetBool("yaml.bool"))
+	})
+
+	t.Run("with missing embedded file", func(t *testing.T) {
+		t.Parallel()
+		_, err := config.LoadEmbed([]string{"config/missing.yml"}, embed, logger)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "config/missing.yml")
+	})
+
+	t.Run("with gzipped and plain embedded files", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		_, err := zw.Write([]byte(firstMockFilesYaml))
+		require.NoError(t, err)
+		require.NoError(t, zw.Close())
+
+		mixed := fstest.MapFS{
+			"config/first.yml.gz": {Data: buf.Bytes()},
+			"config/second.yml":   {Data: []byte(secondMockFilesYaml)},
+		}
+
+		c, err := config.LoadEmbed([]string{"config/first.yml.gz", "config/second.yml"}, mixed, logger)
+		require.NoError(t, err)
+		assert.Equal(t, "value2", c.GetString("yaml.key"))
+		assert.True(t, c.GetBool("yaml.bool"))
+	})
+}
+
+func TestLoadEmbedAll(t *testing.T) {
+	t.Parallel()
+	logger := log.New(io.Discard)
+	embed := fstest.MapFS{
+		"config/first.yml":  {Data: []byte(firstMockFilesYaml)},
+		"config/second.yml": {Data: []byte(secondMockFilesYaml)},
+	}
+
+	t.Run("with all files present", func(t *testing.T) {
+		t.Parallel()
+		c, err := config.LoadEmbedAll([]string{"config/first.yml", "config/second.yml"}, embed, logger)
+		require.NoError(t, err)
+		assert.Equal(t, "value2", c.GetString("yaml.key"))
+	})
+
+	t.Run("with multiple missing files", func(t *testing.T) {
+		t.Parallel()
+		_, err := config.LoadEmbedAll([]string{"config/a.yml", "config/first.yml", "config/b.yml", "config/c.yml"}, embed, logger)
+		require.Error(t, err)
+		require.ErrorIs(t, err, fs.ErrNotExist)
+		for _, missing := range []string{"config/a.yml", "config/b.yml", "config/c.yml"} {
+			assert.Contains(t, err.Error(), missing)
+		}
+		assert.NotContains(t, err.Error(), "config/first.yml")
+	})
+}
+
+func TestMustLoadEmbed(t *testing.T) {
+	t.Parallel()
+	logger := log.New(io.Discard)
+	embed := fstest.MapFS{"defaults.yml": {Data: []byte(embeddedMockYaml)}}
+
+	t.Run("with existing file", func(t *testing.T) {
+		t.Parallel()
+		c := config.MustLoadEmbed([]string{"defaults.yml"}, embed, logger)
+		assert.Equal(t, "embedded", c.GetString("fallback.key"))
+	})
+
+	t.Run("with missing file", func(t *testing.T) {
+		t.Parallel()
+		assert.Panics(t, func() {
+			config.MustLoadEmbed([]string{"missing.yml"}, embed, logger)
+		})
+	})
+}
+
+func TestLoadEmbedLenient(t *testing.T) {
+	t.Parallel()
+	logger := log.New(io.Discard)
+	embed := fstest.MapFS{
+		"config/first.yml":  {Data: []byte(firstMockFilesYaml)},
+		"config/second.yml": {Data: []byte(secondMockFilesYaml)},
+		"config/dir":        {Mode: fs.ModeDir},
+	}
+
+	t.Run("with one of three files absent", func(t *testing.T) {
+		t.Parallel()
+		c, err := config.LoadEmbedLenient([]string{"config/first.yml", "config/absent.yml", "config/second.yml"}, embed, logger)
+		require.NoError(t, err)
+		assert.Equal(t, "value2", c.GetString("yaml.key"))
+		assert.True(t, c.GetBool("yaml.bool"))
+	})
+
+	t.Run("with a non not-exist failure", func(t *testing.T) {
+		t.Parallel()
+		_, err := config.LoadEmbedLenient([]string{"config/first.yml", "config/dir"}, embed, logger)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "1 of 2 files read")
+	})
+}
+
+func TestLoadEmbedGlob(t *testing.T) {
+	t.Parallel()
+	logger := log.New(io.Discard)
+	embed := fstest.MapFS{
+		"config/b.yaml":   {Data: []byte(secondMockFilesYaml)},
+		"config/a.yaml":   {Data: []byte(firstMockFilesYaml)},
+		"config/notes.md": {Data: []byte("# not config")},
+	}
+
+	t.Run("with multiple matched files", func(t *testing.T) {
+		t.Parallel()
+		c, err := config.LoadEmbedGlob("config/*.yaml", embed, logger)
+		require.NoError(t, err)
+		assert.Equal(t, "value2", c.GetString("yaml.key"))
+		assert.Equal(t, "secondfile", c.GetString("yaml.more.key2"))
+		assert.True(t, c.GetBool("yaml.bool"))
+	})
+
+	t.Run("with pattern matching nothing", func(t *testing.T) {
+		t.Parallel()
+		_, err := config.LoadEmbedGlob("config/*.json", embed, logger)
+		require.ErrorIs(t, err, config.ErrNoFilesFound)
+	})
+
+	t.Run("with malformed pattern", func(t *testing.T) {
+		t.Parallel()
+		_, err := config.LoadEmbedGlob("config/[", embed, logger)
+		require.Error(t, err)
+	})
+}
+
+func TestLoadReader(t *testing.T) {
+	t.Parallel()
+	logger := log.New(io.Discard)
+
+	t.Run("with base", func(t *testing.T) {
+		t.Parallel()
+		base := config.NewReaderContainer(logger, "yaml", strings.NewReader(firstMockFilesYaml))
+		stdin := bytes.NewBufferString(`{"yaml": {"key": "from-stdin"}}`)
+
+		c, err := config.LoadReader(stdin, "json", logger, base)
+		require.NoError(t, err)
+		assert.Equal(t, "from-stdin", c.GetString("yaml.key"))
+		assert.True(t, c.GetBool("yaml.bool"))
+		assert.Equal(t, "value", base.GetString("yaml.key"))
+	})
+
+	t.Run("without base", func(t *testing.T) {
+		t.Parallel()
+		c, err := config.LoadReader(bytes.NewBufferString(`{"yaml": {"key": "from-stdin"}}`), "json", logger, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "from-stdin", c.GetString("yaml.key"))
+	})
+
+	t.Run("with empty input", func(t *testing.T) {
+		t.Parallel()
+		base := config.NewReaderContainer(logger, "yaml", strings.NewReader(firstMockFilesYaml))
+
+		c, err := config.LoadReader(&bytes.Buffer{}, "json", logger, base)
+		require.NoError(t, err)
+		assert.Same(t, base, c)
+
+		c, err = config.LoadReader(nil, "json", logger, nil)
+		require.NoError(t, err)
+		assert.NotNil(t, c)
+	})
+
+	t.Run("with invalid input", func(t *testing.T) {
+		t.Parallel()
+		_, err := config.LoadReader(bytes.NewBufferString("{not json"), "json", logger, nil)
+		require.Error(t, err)
+	})
+}
+
+func TestLoadZip(t *testing.T) {
+	t.Parallel()
+	logger := log.New(io.Discard)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		"config/first.yml":  firstMockFilesYaml,
+		"config/second.yml": secondMockFilesYaml,
+	} {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = io.WriteString(w, content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	archive := bytes.NewReader(buf.Bytes())
+
+	t.Run("with multiple entries", func(t *testing.T) {
+		t.Parallel()
+		c, err := config.LoadZip(archive, archive.Size(), []string{"config/first.yml", "config/second.yml"}, logger)
+		require.NoError(t, err)
+		assert.Equal(t, "value2", c.GetString("yaml.key"))
+		assert.True(t, c.GetBool("yaml.bool"))
+	})
+
+	t.Run("with missing entry", func(t *testing.T) {
+		t.Parallel()
+		_, err := config.LoadZip(archive, archive.Size(), []string{"config/missing.yml"}, logger)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "config/missing.yml")
+	})
+
+	t.Run("with invalid archive", func(t *testing.T) {
+		t.Parallel()
+		_, err := config.LoadZip(strings.NewReader("not a zip"), 9, []string{"config/first.yml"}, logger)
+		require.Error(t, err)
+	})
+}
+
+func TestLoadWithFallback(t *testing.T) {
+	logger := log.New(io.Discard)
+	embed := fstest.MapFS{"defaults.yml": {Data: []byte(embeddedMockYaml)}}
+
+	t.Run("with files present", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		err := afero.WriteFile(fs, "first.yml", []byte(firstMockFilesYaml), 0o644)
+		require.NoError(t, err)
+
+		c, err := config.LoadWithFallback([]string{"first.yml"}, fs, embed, []string{"defaults.yml"}, logger)
+		require.NoError(t, err)
+		assert.Equal(t, "value", c.GetString("yaml.key"))
+		assert.Empty(t, c.GetString("fallback.key"))
+	})
+
+	t.Run("with no files falling back to embedded defaults", func(t *testing.T) {
+		t.Setenv("FALLBACK_OTHER", "from-env")
+
+		c, err := config.LoadWithFallback([]string{"missing.yml"}, afero.NewMemMapFs(), embed, []string{"defaults.yml"}, logger)
+		require.NoError(t, err)
+		assert.Equal(t, "embedded", c.GetString("fallback.key"))
+		assert.Equal(t, "from-env", c.GetString("fallback.other"))
+	})
+}
+
+func TestLoadFS(t *testing.T) {
+	t.Parallel()
+	logger := log.New(io.Discard)
+	fsys := fstest.MapFS{
+		"config/base.yaml":     {Data: []byte(firstMockFilesYaml)},
+		"config/override.json": {Data: []byte(`{"yaml": {"key": "json", "more": {"key2": "fromjson"}}}`)},
+		"config/unknown.conf":  {Data: []byte("ignored")},
+	}
+
+	t.Run("with yaml and json files", func(t *testing.T) {
+		t.Parallel()
+		c, err := config.LoadFS([]string{"config/base.yaml", "config/override.json"}, fsys, logger)
+		require.NoError(t, err)
+		assert.Equal(t, "json", c.GetString("yaml.key"))
+		assert.Equal(t, "fromjson", c.GetString("yaml.more.key2"))
+		assert.Equal(t, 1, c.GetInt("yaml.int"))
+	})
+
+	t.Run("with missing file", func(t *testing.T) {
+		t.Parallel()
+		_, err := config.LoadFS([]string{"config/missing.yaml"}, fsys, logger)
+		require.ErrorIs(t, err, fs.ErrNotExist)
+	})
+
+	t.Run("with unsupported extension", func(t *testing.T) {
+		t.Parallel()
+		_, err := config.LoadFS([]string{"config/unknown.conf"}, fsys, logger)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported config format")
+	})
+}
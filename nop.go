@@ -0,0 +1,92 @@
+package config
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// nopContainer Containable in which no key is ever set and every operation is a safe no-op.
+type nopContainer struct{}
+
+// NewNopContainer return a Containable for code that can run without config. Every getter returns the
+// zero value, Has and IsSet return false, Sub returns the container itself and observers are never fired.
+func NewNopContainer() Containable {
+	return nopContainer{}
+}
+
+func (nopContainer) Get(string) interface{} {
+	return nil
+}
+
+func (nopContainer) GetBool(string) bool {
+	return false
+}
+
+func (nopContainer) GetInt(string) int {
+	return 0
+}
+
+func (nopContainer) GetInt64(string) int64 {
+	return 0
+}
+
+func (nopContainer) GetInt32(string) int32 {
+	return 0
+}
+
+func (nopContainer) GetUint(string) uint {
+	return 0
+}
+
+func (nopContainer) GetUint64(string) uint64 {
+	return 0
+}
+
+func (nopContainer) GetFloat(string) float64 {
+	return 0
+}
+
+func (nopContainer) GetString(string) string {
+	return ""
+}
+
+func (nopContainer) GetStringMapStringSlice(string) map[string][]string {
+	return nil
+}
+
+func (nopContainer) GetTime(string) time.Time {
+	return time.Time{}
+}
+
+func (nopContainer) GetDuration(string) time.Duration {
+	return 0
+}
+
+func (nopContainer) GetViper() *viper.Viper {
+	return viper.New()
+}
+
+func (nopContainer) Has(string) bool {
+	return false
+}
+
+func (nopContainer) IsSet(string) bool {
+	return false
+}
+
+func (n nopContainer) Sub(string) Containable {
+	return n
+}
+
+func (nopContainer) AddObserver(Observable) {}
+
+func (nopContainer) AddObserverFunc(func(Containable, chan error)) func() {
+	return func() {}
+}
+
+func (nopContainer) ToJSON() string {
+	return "{}"
+}
+
+func (nopContainer) Dump() {}
@@ -0,0 +1,34 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/phpboyscout/config"
+)
+
+func TestNewNopContainer(t *testing.T) {
+	t.Parallel()
+	c := config.NewNopContainer()
+
+	c.AddObserver(config.Observer{})
+	detach := c.AddObserverFunc(func(config.Containable, chan error) {})
+	detach()
+	c.Dump()
+
+	assert.Nil(t, c.Get("key"))
+	assert.False(t, c.GetBool("key"))
+	assert.Zero(t, c.GetInt("key"))
+	assert.Zero(t, c.GetFloat("key"))
+	assert.Empty(t, c.GetString("key"))
+	assert.Empty(t, c.GetStringMapStringSlice("key"))
+	assert.True(t, c.GetTime("key").IsZero())
+	assert.Zero(t, c.GetDuration("key"))
+	assert.NotNil(t, c.GetViper())
+	assert.False(t, c.Has("key"))
+	assert.False(t, c.IsSet("key"))
+	assert.Equal(t, c, c.Sub("key"))
+
+	assert.Equal(t, "{}", c.ToJSON())
+}
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/go-errors/errors"
+)
+
+// NormalizeKeySeparators collapse camelCase, snake_case and kebab-case spellings of a key segment to one
+// form by lowercasing and dropping "_" and "-", so that maxConnections, max_connections and max-connections
+// all become maxconnections. Viper lowercases keys on load, so word boundaries can't be recovered from case.
+func NormalizeKeySeparators(segment string) string {
+	return strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(segment))
+}
+
+// SetKeyNormalizer apply f to each dotted segment of loaded config keys and of keys passed to the getters,
+// so that different spellings of a key resolve to the same value. Config files are normalized as they are
+// reloaded, keeping defaults, overrides and flag and env bindings. Containers built from readers have their
+// config rebuilt from the merged settings, which flattens env values into it, so set the normalizer straight
+// after construction.
+func (c *Container) SetKeyNormalizer(f func(string) string) {
+	if c.ignoreFrozen("SetKeyNormalizer") {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.keyNormalizer = f
+	if err := c.normalizeKeys(); err != nil {
+		c.logger.Warn("unable to normalize config keys", "stacktrace", errors.Wrap(err, 0).ErrorStack())
+	}
+}
+
+// normalizeKeys replace the container's file config with the same config with every key normalized. Loaded
+// files are read again; other sources can't be, so the merged settings stand in for them.
+func (c *Container) normalizeKeys() error {
+	if len(c.loadedFiles) == 0 {
+		return c.replaceFileConfig([]map[string]interface{}{c.viper.AllSettings()})
+	}
+
+	sources := make([]map[string]interface{}, 0, len(c.loadedFiles))
+	for _, f := range c.loadedFiles {
+		if c.skipEmptyFile(f) {
+			continue
+		}
+
+		settings, err := c.fileSettings(f)
+		if err != nil {
+			return errors.Errorf("%s: %w", f, err)
+		}
+		sources = append(sources, settings)
+	}
+
+	return c.replaceFileConfig(sources)
+}
+
+// replaceFileConfig swap the container's file config for sources merged in order, normalizing their keys if
+// a normalizer is set. Defaults, overrides and bindings live in other layers of viper and are left in place.
+func (c *Container) replaceFileConfig(sources []map[string]interface{}) error {
+	// viper has no call to clear its file config, but ReadConfig replaces it before decoding, so it is left
+	// empty even when a format such as JSON rejects the empty document.
+	_ = c.viper.ReadConfig(bytes.NewReader(nil))
+
+	for _, settings := range sources {
+		if c.keyNormalizer != nil {
+			settings, _ = normalizeValue(settings, c.keyNormalizer).(map[string]interface{})
+		}
+
+		if err := c.viper.MergeConfigMap(settings); err != nil {
+			return errors.Wrap(err, 0)
+		}
+	}
+
+	return nil
+}
+
+// normalizeValue apply f to the keys of every map within value, recursing into nested maps and lists.
+func normalizeValue(value interface{}, f func(string) string) interface{} {
+	switch t := value.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, v := range t {
+			m[f(k)] = normalizeValue(v, f)
+		}
+
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(t))
+		for i, v := range t {
+			l[i] = normalizeValue(v, f)
+		}
+
+		return l
+	default:
+		return value
+	}
+}
+
+// canonicalKey translate a key into the form held by viper.
+func (c *Container) canonicalKey(key string) string {
+	key = indexedKey(key)
+	if c.keyNormalizer == nil {
+		return key
+	}
+
+	segments := strings.Split(key, ".")
+	for i, s := range segments {
+		segments[i] = c.keyNormalizer(s)
+	}
+
+	return strings.Join(segments, ".")
+}
@@ -0,0 +1,71 @@
+package config_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/phpboyscout/config"
+)
+
+func TestNormalizeKeySeparators(t *testing.T) {
+	t.Parallel()
+
+	for _, k := range []string{"maxConnections", "max_connections", "max-connections", "MAX_CONNECTIONS"} {
+		assert.Equal(t, "maxconnections", config.NormalizeKeySeparators(k), k)
+	}
+}
+
+func TestContainer_SetKeyNormalizer(t *testing.T) {
+	t.Parallel()
+	l := log.New(io.Discard)
+	c := config.NewReaderContainer(l, "yaml", strings.NewReader(`database:
+  maxConnections: 10
+  read-timeout: 5s
+  replicaHosts:
+    - hostName: a
+    - hostName: b`))
+
+	c.SetKeyNormalizer(config.NormalizeKeySeparators)
+
+	assert.Equal(t, 10, c.GetInt("database.max_connections"))
+	assert.Equal(t, 10, c.GetInt("database.max-connections"))
+	assert.Equal(t, 10, c.GetInt("database.maxConnections"))
+	assert.Equal(t, "5s", c.GetString("database.read_timeout"))
+	assert.Equal(t, "b", c.GetString("database.replica_hosts[1].host_name"))
+	assert.True(t, c.IsSet("database.max_connections"))
+	assert.Equal(t, 10, c.Sub("database").GetInt("max_connections"))
+
+	c.Set("database.max_connections", 20)
+	assert.Equal(t, 20, c.GetInt("database.maxConnections"))
+}
+
+func TestContainer_SetKeyNormalizer_Reload(t *testing.T) {
+	l := log.New(io.Discard)
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "config.yml", []byte("db:\n  maxConnections: 10\n  pass: file"), 0o644))
+
+	c := config.NewFilesContainer(l, fs, "config.yml")
+	c.SetKeyNormalizer(config.NormalizeKeySeparators)
+	c.GetViper().SetDefault("db.idletimeout", "30s")
+	require.NoError(t, c.BindEnv("db.pass", "SECRET_PW"))
+
+	t.Setenv("SECRET_PW", "first")
+	assert.Equal(t, "first", c.GetString("db.pass"))
+
+	require.NoError(t, afero.WriteFile(fs, "config.yml", []byte("db:\n  max-connections: 20\n  pass: file"), 0o644))
+	require.NoError(t, c.Reset())
+
+	t.Setenv("SECRET_PW", "second")
+	assert.Equal(t, "second", c.GetString("db.pass"))
+	assert.Equal(t, config.SourceEnv, c.Explain("db.pass").Source)
+	assert.Equal(t, 20, c.GetInt("db.max_connections"))
+	assert.Equal(t, config.SourceFile, c.Explain("db.max_connections").Source)
+	assert.Equal(t, "30s", c.GetString("db.idle_timeout"))
+	assert.Equal(t, "config.yml", c.ConfigFileUsed())
+}
@@ -1,5 +1,13 @@
 package config
 
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/fsnotify/fsnotify"
+)
+
 type Observable interface {
 	Run(Containable, chan error)
 }
@@ -11,3 +19,95 @@ type Observer struct {
 func (o Observer) Run(c Containable, errs chan error) {
 	o.handler(c, errs)
 }
+
+// SetSequentialObservers run observers one at a time in registration order when enabled, so that an
+// observer can rely on state set by those registered before it. By default observers run concurrently.
+func (c *Container) SetSequentialObservers(sequential bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sequentialObservers = sequential
+}
+
+// AddEventObserver attach function to trigger on config file update, receiving the file event that caused
+// it so that observers can react to which file changed and how.
+func (c *Container) AddEventObserver(f func(Containable, fsnotify.Event)) {
+	if c.ignoreFrozen("AddEventObserver") {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.eventObservers = append(c.eventObservers, f)
+}
+
+// subtreeObserver callback fired when the settings under prefix change.
+type subtreeObserver struct {
+	prefix  string
+	handler func(Containable)
+	hash    [sha256.Size]byte
+}
+
+// AddSubtreeObserver attach function to trigger on config update only when a key under prefix has changed.
+// The callback receives the subtree as returned by Sub(prefix).
+func (c *Container) AddSubtreeObserver(prefix string, f func(Containable)) {
+	if c.ignoreFrozen("AddSubtreeObserver") {
+		return
+	}
+
+	o := &subtreeObserver{
+		prefix:  prefix,
+		handler: f,
+		hash:    c.subtreeHash(prefix),
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.subtreeObservers = append(c.subtreeObservers, o)
+}
+
+// notifySubtreeObservers fire each subtree observer whose subtree hash differs from the last one seen.
+func (c *Container) notifySubtreeObservers() {
+	c.mu.RLock()
+	observers := c.subtreeObservers
+	c.mu.RUnlock()
+
+	for _, o := range observers {
+		hash := c.subtreeHash(o.prefix)
+		if hash == o.hash {
+			continue
+		}
+
+		o.hash = hash
+		c.guard("subtree observer", func() { o.handler(c.Sub(o.prefix)) })
+	}
+}
+
+// subtreeHash hash the settings under prefix. JSON encoding sorts map keys so the result is stable.
+func (c *Container) subtreeHash(prefix string) [sha256.Size]byte {
+	c.mu.RLock()
+	bs, err := json.Marshal(c.viper.Get(prefix))
+	c.mu.RUnlock()
+	if err != nil {
+		c.logger.Warn("unable to hash config subtree", "prefix", prefix, "error", err)
+	}
+
+	return sha256.Sum256(bs)
+}
+
+// ContentHash return a stable hex-encoded SHA-256 of the effective settings, for telling whether config
+// actually changed. JSON encoding sorts map keys, so identical content always hashes the same.
+func (c *Container) ContentHash() string {
+	c.mu.RLock()
+	bs, err := json.Marshal(c.viper.AllSettings())
+	c.mu.RUnlock()
+	if err != nil {
+		c.logger.Warn("unable to hash config", "error", err)
+	}
+
+	sum := sha256.Sum256(bs)
+
+	return hex.EncodeToString(sum[:])
+}
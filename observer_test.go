@@ -1 +1,237 @@
 package config_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/phpboyscout/config"
+)
+
+func TestContainer_AddSubtreeObserver(t *testing.T) {
+	t.Parallel()
+	l := log.New(io.Discard)
+	initial := "features:\n  auth: true\nserver:\n  port: 80"
+
+	t.Run("with sibling subtree changed", func(t *testing.T) {
+		t.Parallel()
+		c := config.NewReaderContainer(l, "yaml", strings.NewReader(initial))
+
+		var triggered, reloads int32
+		c.AddSubtreeObserver("features", func(config.Containable) { atomic.AddInt32(&triggered, 1) })
+		c.AddObserverFunc(func(config.Containable, chan error) { atomic.AddInt32(&reloads, 1) })
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		c.StartPolling(ctx, 10*time.Millisecond, func() (config.Containable, error) {
+			return config.NewReaderContainer(l, "yaml", strings.NewReader("features:\n  auth: true\nserver:\n  port: 8080")), nil
+		})
+
+		assert.Eventually(t, func() bool { return atomic.LoadInt32(&reloads) == 1 }, time.Second, 5*time.Millisecond)
+		assert.Zero(t, atomic.LoadInt32(&triggered))
+	})
+
+	t.Run("with watched subtree changed", func(t *testing.T) {
+		t.Parallel()
+		c := config.NewReaderContainer(l, "yaml", strings.NewReader(initial))
+
+		var triggered int32
+		var auth atomic.Value
+		c.AddSubtreeObserver("features", func(s config.Containable) {
+			atomic.AddInt32(&triggered, 1)
+			auth.Store(s.GetBool("auth"))
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		c.StartPolling(ctx, 10*time.Millisecond, func() (config.Containable, error) {
+			return config.NewReaderContainer(l, "yaml", strings.NewReader("features:\n  auth: false\nserver:\n  port: 80")), nil
+		})
+
+		assert.Eventually(t, func() bool { return atomic.LoadInt32(&triggered) == 1 }, time.Second, 5*time.Millisecond)
+		time.Sleep(50 * time.Millisecond)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&triggered))
+		assert.Equal(t, false, auth.Load())
+	})
+}
+
+func TestContainer_AddEventObserver(t *testing.T) {
+	t.Parallel()
+	l := log.New(io.Discard)
+	filename := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(filename, []byte(firstMockFilesYaml), 0o600))
+
+	c := config.NewFilesContainer(l, afero.NewOsFs(), filename)
+
+	events := make(chan fsnotify.Event, 10)
+	c.AddEventObserver(func(_ config.Containable, e fsnotify.Event) {
+		events <- e
+	})
+
+	require.NoError(t, os.WriteFile(filename, []byte(secondMockFilesYaml), 0o600))
+
+	select {
+	case e := <-events:
+		assert.Equal(t, filename, e.Name)
+		assert.True(t, e.Has(fsnotify.Write))
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected an event observer to be triggered")
+	}
+}
+
+func TestContainer_SetSequentialObservers(t *testing.T) {
+	t.Parallel()
+	l := log.New(io.Discard)
+	c := config.NewReaderContainer(l, "yaml", strings.NewReader(firstMockFilesYaml))
+	c.SetSequentialObservers(true)
+
+	var mu sync.Mutex
+	order := make([]int, 0)
+	for i := 0; i < 5; i++ {
+		i := i
+		c.AddObserverFunc(func(config.Containable, chan error) {
+			// earlier observers take longer, so concurrent execution would finish out of order
+			time.Sleep(time.Duration(5-i) * 5 * time.Millisecond)
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, i)
+		})
+	}
+
+	require.NoError(t, c.Reset())
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []int{0, 1, 2, 3, 4}, order)
+}
+
+func TestContainer_ObserverPanic(t *testing.T) {
+	t.Parallel()
+
+	for _, sequential := range []bool{false, true} {
+		sequential := sequential
+		t.Run(fmt.Sprintf("with sequential %v", sequential), func(t *testing.T) {
+			t.Parallel()
+			buf := &syncBuffer{}
+			c := config.NewReaderContainer(log.New(buf), "yaml", strings.NewReader(firstMockFilesYaml))
+			c.SetSequentialObservers(sequential)
+			hook := &fakeReloadHook{}
+			c.SetReloadHook(hook)
+
+			var ran int32
+			c.AddObserverFunc(func(config.Containable, chan error) {
+				panic("observer exploded")
+			})
+			c.AddObserverFunc(func(config.Containable, chan error) {
+				atomic.AddInt32(&ran, 1)
+			})
+
+			require.NotPanics(t, func() { require.NoError(t, c.Reset()) })
+
+			assert.Equal(t, int32(1), atomic.LoadInt32(&ran))
+			assert.Contains(t, buf.String(), "config observer panicked")
+			assert.Contains(t, buf.String(), "observer exploded")
+			_, errCount := hook.counts()
+			assert.Equal(t, 1, errCount)
+		})
+	}
+}
+
+func TestContainer_CallbackPanic(t *testing.T) {
+	t.Parallel()
+
+	t.Run("with subtree observers and finalizers", func(t *testing.T) {
+		t.Parallel()
+		buf := &syncBuffer{}
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, "config.yml", []byte(firstMockFilesYaml), 0o644))
+
+		c := config.NewFilesContainer(log.New(buf), fs, "config.yml")
+		t.Cleanup(func() { _ = c.Close() })
+
+		var finalized int32
+		c.AddSubtreeObserver("yaml", func(config.Containable) { panic("subtree observer exploded") })
+		c.AddFinalizer(func(config.Containable) error {
+			atomic.AddInt32(&finalized, 1)
+
+			return nil
+		})
+		c.AddFinalizer(func(config.Containable) error { panic("finalizer exploded") })
+
+		require.NoError(t, afero.WriteFile(fs, "config.yml", []byte(secondMockFilesYaml), 0o644))
+		require.NotPanics(t, func() { require.NoError(t, c.Reset()) })
+
+		assert.Equal(t, int32(1), atomic.LoadInt32(&finalized))
+		assert.Contains(t, buf.String(), "config subtree observer panicked")
+		assert.Contains(t, buf.String(), "config finalizer panicked")
+	})
+
+	t.Run("with event observers", func(t *testing.T) {
+		t.Parallel()
+		buf := &syncBuffer{}
+		file := filepath.Join(t.TempDir(), "config.yml")
+		require.NoError(t, os.WriteFile(file, []byte(firstMockFilesYaml), 0o600))
+
+		c := config.NewFilesContainer(log.New(buf), afero.NewOsFs(), file)
+		t.Cleanup(func() { _ = c.Close() })
+
+		var observed int32
+		c.AddEventObserver(func(config.Containable, fsnotify.Event) { panic("event observer exploded") })
+		c.AddObserverFunc(func(config.Containable, chan error) { atomic.AddInt32(&observed, 1) })
+
+		require.NoError(t, os.WriteFile(file, []byte(secondMockFilesYaml), 0o600))
+
+		assert.Eventually(t, func() bool { return atomic.LoadInt32(&observed) >= 1 }, 2*time.Second, 10*time.Millisecond)
+		assert.Contains(t, buf.String(), "config event observer panicked")
+		assert.Equal(t, "value2", c.GetString("yaml.key"))
+	})
+}
+
+// syncBuffer bytes.Buffer safe for the concurrent writes of observers logging.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
+
+func TestContainer_ContentHash(t *testing.T) {
+	t.Parallel()
+	l := log.New(io.Discard)
+
+	a := config.NewReaderContainer(l, "yaml", strings.NewReader("server:\n  host: local\n  port: 80\nname: app"))
+	b := config.NewReaderContainer(l, "yaml", strings.NewReader("name: app\nserver:\n  port: 80\n  host: local"))
+
+	assert.Len(t, a.ContentHash(), 64)
+	assert.Equal(t, a.ContentHash(), a.ContentHash())
+	assert.Equal(t, a.ContentHash(), b.ContentHash())
+
+	b.Set("server.port", 8080)
+	assert.NotEqual(t, a.ContentHash(), b.ContentHash())
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"github.com/spf13/afero"
+)
+
+// insecurePermissions mode bits granting group or other access to a file.
+const insecurePermissions = 0o077
+
+// WarnInsecurePermissions log a warning for each file that is accessible by group or other users.
+// This is opt-in, intended for files holding secrets, and is skipped on filesystems such as MemMapFs that
+// don't report meaningful modes.
+func (c *Container) WarnInsecurePermissions(fs afero.Fs, paths ...string) {
+	if !reportsFileModes(fs) {
+		return
+	}
+
+	for _, p := range paths {
+		info, err := fs.Stat(p)
+		if err != nil {
+			c.logger.Debug("unable to stat config file for permission check", "file", p, "error", err)
+
+			continue
+		}
+
+		if mode := info.Mode().Perm(); mode&insecurePermissions != 0 {
+			c.logger.Warn("config file is accessible by other users", "file", p, "mode", mode.String())
+		}
+	}
+}
+
+// reportsFileModes reports whether fs is backed by the OS filesystem and so has meaningful permission bits.
+func reportsFileModes(fs afero.Fs) bool {
+	_, ok := fs.(*afero.OsFs)
+
+	return ok
+}
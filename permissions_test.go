@@ -0,0 +1,58 @@
+package config_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/phpboyscout/config"
+)
+
+func TestContainer_WarnInsecurePermissions(t *testing.T) {
+	t.Parallel()
+
+	newContainer := func(t *testing.T) (*config.Container, *bytes.Buffer) {
+		t.Helper()
+		buf := &bytes.Buffer{}
+
+		return config.NewReaderContainer(log.New(buf), "yaml"), buf
+	}
+
+	t.Run("with world-readable file", func(t *testing.T) {
+		t.Parallel()
+		filename := filepath.Join(t.TempDir(), "secrets.yml")
+		require.NoError(t, os.WriteFile(filename, []byte("secret: value"), 0o600))
+		require.NoError(t, os.Chmod(filename, 0o644))
+
+		c, buf := newContainer(t)
+		c.WarnInsecurePermissions(afero.NewOsFs(), filename)
+		assert.Contains(t, buf.String(), "accessible by other users")
+		assert.Contains(t, buf.String(), filename)
+	})
+
+	t.Run("with owner-only file", func(t *testing.T) {
+		t.Parallel()
+		filename := filepath.Join(t.TempDir(), "secrets.yml")
+		require.NoError(t, os.WriteFile(filename, []byte("secret: value"), 0o600))
+
+		c, buf := newContainer(t)
+		c.WarnInsecurePermissions(afero.NewOsFs(), filename)
+		assert.NotContains(t, buf.String(), "accessible by other users")
+	})
+
+	t.Run("with MemMapFs", func(t *testing.T) {
+		t.Parallel()
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, "secrets.yml", []byte("secret: value"), 0o644))
+
+		c, buf := newContainer(t)
+		c.WarnInsecurePermissions(fs, "secrets.yml")
+		assert.NotContains(t, buf.String(), "accessible by other users")
+	})
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/go-errors/errors"
+)
+
+// StartPolling periodically invoke reload to refresh config from sources that can't be watched, such as
+// HTTP or embedded config. When the reloaded settings differ from the current ones they are swapped in
+// and observers are fired. Polling stops when ctx is cancelled.
+func (c *Container) StartPolling(ctx context.Context, interval time.Duration, reload func() (Containable, error)) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				c.poll(reload)
+			}
+		}
+	}()
+}
+
+// poll run a single reload, swapping in the new settings and notifying observers if they changed. Only the
+// file config is replaced, so defaults, overrides and env and flag bindings carry over.
+func (c *Container) poll(reload func() (Containable, error)) {
+	next, err := reload()
+	if err != nil {
+		c.logger.Warn("unable to reload config", "stacktrace", errors.Wrap(err, 0).ErrorStack())
+
+		return
+	}
+
+	before := c.changeSnapshot()
+
+	c.mu.Lock()
+	current := c.viper.AllSettings()
+	if err := c.replaceFileConfig([]map[string]interface{}{next.GetViper().AllSettings()}); err != nil {
+		c.mu.Unlock()
+		c.logger.Warn("unable to apply polled config", "stacktrace", errors.Wrap(err, 0).ErrorStack())
+
+		return
+	}
+	changed := !reflect.DeepEqual(current, c.viper.AllSettings())
+	c.mu.Unlock()
+
+	if !changed {
+		return
+	}
+
+	c.logger.Info("Config updated by poll")
+	c.publishChange(nil, before)
+	c.notifyObservers()
+}
@@ -0,0 +1,127 @@
+package config_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/go-errors/errors"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/phpboyscout/config"
+)
+
+func TestContainer_StartPolling(t *testing.T) {
+	t.Parallel()
+	l := log.New(io.Discard)
+
+	t.Run("with content changing on second poll", func(t *testing.T) {
+		t.Parallel()
+		c := config.NewReaderContainer(l, "yaml", strings.NewReader(firstMockFilesYaml))
+
+		var polls, observed int32
+		var observedValue atomic.Value
+		c.AddObserverFunc(func(c config.Containable, _ chan error) {
+			atomic.AddInt32(&observed, 1)
+			observedValue.Store(c.GetString("yaml.key"))
+		})
+
+		reload := func() (config.Containable, error) {
+			if atomic.AddInt32(&polls, 1) < 2 {
+				return config.NewReaderContainer(l, "yaml", strings.NewReader(firstMockFilesYaml)), nil
+			}
+
+			return config.NewReaderContainer(l, "yaml", strings.NewReader(secondMockFilesYaml)), nil
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		c.StartPolling(ctx, 10*time.Millisecond, reload)
+
+		assert.Eventually(t, func() bool { return atomic.LoadInt32(&polls) >= 5 }, time.Second, 5*time.Millisecond)
+		cancel()
+
+		assert.Equal(t, int32(1), atomic.LoadInt32(&observed))
+		assert.Equal(t, "value2", observedValue.Load())
+	})
+
+	t.Run("keeps defaults, overrides and flags", func(t *testing.T) {
+		t.Parallel()
+		c := config.NewReaderContainer(l, "yaml", strings.NewReader(firstMockFilesYaml))
+		v := c.GetViper()
+		v.SetDefault("defaulted.key", "default")
+		c.Set("override.key", "set")
+
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		flags.Int("yaml.int", 0, "")
+		require.NoError(t, c.BindPFlags(flags))
+		require.NoError(t, flags.Parse([]string{"--yaml.int=7"}))
+
+		var observed int32
+		c.AddObserverFunc(func(config.Containable, chan error) {
+			atomic.AddInt32(&observed, 1)
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		c.StartPolling(ctx, 10*time.Millisecond, func() (config.Containable, error) {
+			return config.NewReaderContainer(l, "yaml", strings.NewReader(secondMockFilesYaml)), nil
+		})
+
+		assert.Eventually(t, func() bool { return atomic.LoadInt32(&observed) == 1 }, time.Second, 5*time.Millisecond)
+		assert.Equal(t, "value2", c.GetString("yaml.key"))
+		assert.Empty(t, c.GetString("yaml.bool"))
+		assert.Equal(t, "default", c.GetString("defaulted.key"))
+		assert.Equal(t, "set", c.GetString("override.key"))
+		assert.Equal(t, 7, c.GetInt("yaml.int"))
+		assert.Same(t, v, c.GetViper())
+	})
+
+	t.Run("with reload error", func(t *testing.T) {
+		t.Parallel()
+		c := config.NewReaderContainer(l, "yaml", strings.NewReader(firstMockFilesYaml))
+
+		var polls, observed int32
+		c.AddObserverFunc(func(config.Containable, chan error) {
+			atomic.AddInt32(&observed, 1)
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		c.StartPolling(ctx, 10*time.Millisecond, func() (config.Containable, error) {
+			atomic.AddInt32(&polls, 1)
+
+			return nil, errors.New("source unavailable")
+		})
+
+		assert.Eventually(t, func() bool { return atomic.LoadInt32(&polls) >= 2 }, time.Second, 5*time.Millisecond)
+		cancel()
+
+		assert.Zero(t, atomic.LoadInt32(&observed))
+	})
+
+	t.Run("stops on context cancellation", func(t *testing.T) {
+		t.Parallel()
+		c := config.NewReaderContainer(l, "yaml", strings.NewReader(firstMockFilesYaml))
+
+		var polls int32
+		ctx, cancel := context.WithCancel(context.Background())
+		c.StartPolling(ctx, 10*time.Millisecond, func() (config.Containable, error) {
+			atomic.AddInt32(&polls, 1)
+
+			return c, nil
+		})
+
+		assert.Eventually(t, func() bool { return atomic.LoadInt32(&polls) >= 1 }, time.Second, 5*time.Millisecond)
+		cancel()
+		time.Sleep(30 * time.Millisecond)
+
+		stopped := atomic.LoadInt32(&polls)
+		time.Sleep(50 * time.Millisecond)
+		assert.Equal(t, stopped, atomic.LoadInt32(&polls))
+	})
+}
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/cast"
+	"gopkg.in/yaml.v3"
+)
+
+// GetStringMapRaw get the map at key with its keys in their original casing, as written in the loaded
+// config, rather than lowercased as viper returns them. This matters where keys are data, such as HTTP
+// header names. The casing is retained for YAML and JSON sources and registered formats; for anything else,
+// or if key isn't in the retained tree, the map is returned as GetStringMap would return it.
+func (c *Container) GetStringMapRaw(key string) map[string]interface{} {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	k := c.lookupKey(key)
+	if m := rawSubtree(c.raw, k); m != nil {
+		copied, _ := copyRaw(m).(map[string]interface{})
+
+		return copied
+	}
+
+	return c.viper.GetStringMap(k)
+}
+
+// retainRaw parse b, in format or the registered format of source, without lowercasing and merge it into the
+// container's raw tree. Sources that can't be parsed that way are left out of the raw tree.
+func (c *Container) retainRaw(source, format string, b []byte) {
+	var settings map[string]interface{}
+
+	switch strings.ToLower(format) {
+	case "yaml", "yml", "json":
+		if err := yaml.Unmarshal(b, &settings); err != nil {
+			return
+		}
+	default:
+		decoded, ok, err := decodeRegistered(source, b)
+		if !ok || err != nil {
+			return
+		}
+		settings = decoded
+	}
+
+	if c.raw == nil {
+		c.raw = make(map[string]interface{})
+	}
+	mergeRaw(c.raw, settings)
+}
+
+// retainRawFile read f and retain its raw tree, taking the format from the container or f's extension.
+func (c *Container) retainRawFile(f string) {
+	b, err := afero.ReadFile(c.fs, f)
+	if err != nil {
+		return
+	}
+
+	c.retainRawContent(f, b)
+}
+
+// retainRawContent retain the raw tree of b, read from f, taking the format from the container or f's
+// extension.
+func (c *Container) retainRawContent(f string, b []byte) {
+	format := c.format
+	if format == "" {
+		format = strings.TrimPrefix(filepath.Ext(f), ".")
+	}
+
+	c.retainRaw(f, format, b)
+}
+
+// mergeRaw merge src into dst. Keys match case-insensitively, as they do in viper, with the casing of the
+// later source winning. Nested maps are merged; anything else is replaced.
+func mergeRaw(dst, src map[string]interface{}) {
+	for k, v := range src {
+		existing, existingKey := rawLookup(dst, k)
+		if existingKey != "" && existingKey != k {
+			delete(dst, existingKey)
+		}
+
+		srcMap, srcIsMap := v.(map[string]interface{})
+		dstMap, dstIsMap := existing.(map[string]interface{})
+		if srcIsMap && dstIsMap {
+			mergeRaw(dstMap, srcMap)
+			v = dstMap
+		}
+
+		dst[k] = v
+	}
+}
+
+// copyRaw deep copy the maps and lists within value, so that callers can't modify the raw tree.
+func copyRaw(value interface{}) interface{} {
+	switch t := value.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, v := range t {
+			m[k] = copyRaw(v)
+		}
+
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(t))
+		for i, v := range t {
+			l[i] = copyRaw(v)
+		}
+
+		return l
+	default:
+		return value
+	}
+}
+
+// rawLookup find key in m ignoring case, returning the value and the key as it appears in m.
+func rawLookup(m map[string]interface{}, key string) (interface{}, string) {
+	if v, ok := m[key]; ok {
+		return v, key
+	}
+
+	for k, v := range m {
+		if strings.EqualFold(k, key) {
+			return v, k
+		}
+	}
+
+	return nil, ""
+}
+
+// rawSubtree descend the raw tree along the dotted key, ignoring case, returning the map found there or nil.
+// Numeric segments index into lists.
+func rawSubtree(raw map[string]interface{}, key string) map[string]interface{} {
+	if raw == nil {
+		return nil
+	}
+
+	var cur interface{} = raw
+
+	for _, segment := range strings.Split(key, ".") {
+		switch t := cur.(type) {
+		case map[string]interface{}:
+			v, found := rawLookup(t, segment)
+			if found == "" {
+				return nil
+			}
+			cur = v
+		case []interface{}:
+			i, err := strconv.Atoi(segment)
+			if err != nil || i < 0 || i >= len(t) {
+				return nil
+			}
+			cur = t[i]
+		default:
+			return nil
+		}
+	}
+
+	m, err := cast.ToStringMapE(cur)
+	if err != nil || cur == nil {
+		return nil
+	}
+
+	return m
+}
@@ -0,0 +1,86 @@
+package config_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/phpboyscout/config"
+)
+
+var mixedCaseHeadersYaml = `proxy:
+  headers:
+    X-Api-Key: "secret"
+    Content-Type: "application/json"`
+
+func TestContainer_GetStringMapRaw(t *testing.T) {
+	t.Parallel()
+	logger := log.New(io.Discard)
+
+	t.Run("with a reader", func(t *testing.T) {
+		t.Parallel()
+		c := config.NewReaderContainer(logger, "yaml", strings.NewReader(mixedCaseHeadersYaml))
+
+		assert.Equal(t, map[string]interface{}{
+			"X-Api-Key":    "secret",
+			"Content-Type": "application/json",
+		}, c.GetStringMapRaw("proxy.headers"))
+		assert.Equal(t, map[string][]string{
+			"x-api-key":    {"secret"},
+			"content-type": {"application/json"},
+		}, c.GetStringMapStringSlice("proxy.headers"))
+	})
+
+	t.Run("with merged files", func(t *testing.T) {
+		t.Parallel()
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, "base.yml", []byte(mixedCaseHeadersYaml), 0o644))
+		require.NoError(t, afero.WriteFile(fs, "override.json", []byte(`{"proxy": {"headers": {"x-api-key": "other", "X-Trace-Id": "1"}}}`), 0o644))
+
+		c := config.NewFilesContainer(logger, fs, "base.yml", "override.json")
+
+		assert.Equal(t, map[string]interface{}{
+			"x-api-key":    "other",
+			"Content-Type": "application/json",
+			"X-Trace-Id":   "1",
+		}, c.GetStringMapRaw("Proxy.Headers"))
+	})
+
+	t.Run("with the result modified", func(t *testing.T) {
+		t.Parallel()
+		c := config.NewReaderContainer(logger, "yaml", strings.NewReader(mixedCaseHeadersYaml+`
+  routes:
+    Primary:
+      Hosts: ["a"]`))
+
+		m := c.GetStringMapRaw("proxy.headers")
+		m["X-Api-Key"] = "mutated"
+		assert.Equal(t, "secret", c.GetStringMapRaw("proxy.headers")["X-Api-Key"])
+
+		routes := c.GetStringMapRaw("proxy.routes")
+		primary, ok := routes["Primary"].(map[string]interface{})
+		require.True(t, ok)
+		primary["Hosts"].([]interface{})[0] = "mutated"
+		assert.Equal(t, []interface{}{"a"}, c.GetStringMapRaw("proxy.routes.primary")["Hosts"])
+	})
+
+	t.Run("with a sub container", func(t *testing.T) {
+		t.Parallel()
+		c := config.NewReaderContainer(logger, "yaml", strings.NewReader(mixedCaseHeadersYaml))
+
+		sub := c.Sub("proxy").(*config.Container)
+		assert.Equal(t, "secret", sub.GetStringMapRaw("headers")["X-Api-Key"])
+	})
+
+	t.Run("with a missing key", func(t *testing.T) {
+		t.Parallel()
+		c := config.NewReaderContainer(logger, "yaml", strings.NewReader(mixedCaseHeadersYaml))
+
+		assert.Empty(t, c.GetStringMapRaw("proxy.missing"))
+	})
+}
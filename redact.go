@@ -0,0 +1,132 @@
+package config
+
+import (
+	"path"
+	"strings"
+)
+
+// redactedValue replacement for secret values in config output.
+const redactedValue = "***REDACTED***"
+
+// SetSecretKeys mark keys whose values must never appear in ToJSON, ToYAML, Dump or read logging output.
+// Keys are dotted paths and may contain glob patterns matched per segment, e.g. "*.password".
+// Redaction is applied to a copy of the settings so the live config is untouched.
+func (c *Container) SetSecretKeys(keys ...string) {
+	if c.ignoreFrozen("SetSecretKeys") {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.secretKeys = keys
+}
+
+// isSecret report whether key, or one of its ancestors, matches any of the configured secret keys.
+func (c *Container) isSecret(key string) bool {
+	segments := strings.Split(strings.ToLower(c.canonicalKey(key)), ".")
+	for i := range segments {
+		k := strings.Join(segments[:i+1], "/")
+		for _, pattern := range c.secretKeys {
+			matched, err := path.Match(strings.ReplaceAll(strings.ToLower(pattern), ".", "/"), k)
+			if err == nil && matched {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// subSecretKeys rebase the secret key patterns onto the subtree at prefix, for containers derived from it.
+// A pattern matching prefix or one of its ancestors makes the whole subtree secret.
+func (c *Container) subSecretKeys(prefix string) []string {
+	if len(c.secretKeys) == 0 {
+		return nil
+	}
+
+	segments := strings.Split(strings.ToLower(prefix), ".")
+	keys := make([]string, 0, len(c.secretKeys))
+	for _, pattern := range c.secretKeys {
+		if rebased, ok := rebaseSecretKey(strings.Split(strings.ToLower(pattern), "."), segments); ok {
+			keys = append(keys, rebased)
+		}
+	}
+
+	return keys
+}
+
+// rebaseSecretKey strip prefix from the front of pattern, reporting false if pattern doesn't apply beneath it.
+func rebaseSecretKey(pattern, prefix []string) (string, bool) {
+	for i, segment := range prefix {
+		if i == len(pattern) {
+			return "*", true
+		}
+
+		if matched, err := path.Match(pattern[i], segment); err != nil || !matched {
+			return "", false
+		}
+	}
+
+	if len(pattern) == len(prefix) {
+		return "*", true
+	}
+
+	return strings.Join(pattern[len(prefix):], "."), true
+}
+
+// redactedSettings return a deep copy of all settings with secret values replaced.
+func (c *Container) redactedSettings() map[string]interface{} {
+	return c.redactMap(c.viper.AllSettings(), "")
+}
+
+func (c *Container) redactMap(m map[string]interface{}, prefix string) map[string]interface{} {
+	out := make(map[string]interface{}, len(m))
+
+	for k, v := range m {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+
+		if c.isSecret(key) {
+			out[k] = redactedValue
+
+			continue
+		}
+
+		out[k] = c.redactValue(v, key)
+	}
+
+	return out
+}
+
+func (c *Container) redactValue(v interface{}, key string) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		return c.redactMap(t, key)
+	case map[string]string:
+		m := make(map[string]interface{}, len(t))
+		for k, e := range t {
+			m[k] = e
+		}
+
+		return c.redactMap(m, key)
+	case map[string][]string:
+		m := make(map[string]interface{}, len(t))
+		for k, e := range t {
+			m[k] = e
+		}
+
+		return c.redactMap(m, key)
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, e := range t {
+			out[i] = c.redactValue(e, key)
+		}
+
+		return out
+	default:
+		return t
+	}
+}
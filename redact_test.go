@@ -0,0 +1,148 @@
+package config_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/phpboyscout/config"
+)
+
+var redactMockYaml = `database:
+  host: "db.example.com"
+  password: "hunter2"
+cache:
+  password: "swordfish"
+  credentials:
+    token: "abc123"
+api:
+  key: "public"`
+
+func TestContainer_SetSecretKeys(t *testing.T) {
+	t.Parallel()
+	l := log.New(io.Discard)
+
+	t.Run("with dotted path", func(t *testing.T) {
+		t.Parallel()
+		c := config.NewReaderContainer(l, "yaml", strings.NewReader(redactMockYaml))
+		c.SetSecretKeys("database.password")
+
+		out := c.ToJSON()
+		assert.NotContains(t, out, "hunter2")
+		assert.Contains(t, out, `"password":"***REDACTED***"`)
+		assert.Contains(t, out, "db.example.com")
+		assert.Contains(t, out, "swordfish")
+
+		assert.Equal(t, "hunter2", c.GetString("database.password"))
+	})
+
+	t.Run("with glob pattern", func(t *testing.T) {
+		t.Parallel()
+		c := config.NewReaderContainer(l, "yaml", strings.NewReader(redactMockYaml))
+		c.SetSecretKeys("*.password", "cache.credentials")
+
+		out := c.ToYAML()
+		assert.NotContains(t, out, "hunter2")
+		assert.NotContains(t, out, "swordfish")
+		assert.NotContains(t, out, "abc123")
+		assert.Contains(t, out, "db.example.com")
+		assert.Contains(t, out, "public")
+
+		assert.Equal(t, "swordfish", c.GetString("cache.password"))
+		assert.Equal(t, "abc123", c.GetString("cache.credentials.token"))
+	})
+
+	t.Run("with read logging", func(t *testing.T) {
+		t.Parallel()
+		buf := &bytes.Buffer{}
+		logger := log.New(buf)
+		logger.SetLevel(log.DebugLevel)
+
+		c := config.NewReaderContainer(logger, "yaml", strings.NewReader(redactMockYaml)).WithReadLogging()
+		c.SetSecretKeys("*.password")
+
+		assert.Equal(t, "hunter2", c.GetString("database.password"))
+		assert.Equal(t, "db.example.com", c.GetString("database.host"))
+		assert.NotContains(t, buf.String(), "hunter2")
+		assert.Contains(t, buf.String(), "db.example.com")
+	})
+
+	t.Run("with read logging of a parent key", func(t *testing.T) {
+		t.Parallel()
+		buf := &bytes.Buffer{}
+		logger := log.New(buf)
+		logger.SetLevel(log.DebugLevel)
+
+		c := config.NewReaderContainer(logger, "yaml", strings.NewReader(redactMockYaml)).WithReadLogging()
+		c.SetSecretKeys("*.password")
+
+		assert.NotNil(t, c.Get("database"))
+		m, err := c.GetStringMapString("Database")
+		require.NoError(t, err)
+		assert.Equal(t, "hunter2", m["password"])
+		assert.NotEmpty(t, c.GetStringMapStringSlice("cache"))
+
+		assert.NotContains(t, buf.String(), "hunter2")
+		assert.NotContains(t, buf.String(), "swordfish")
+		assert.Contains(t, buf.String(), "db.example.com")
+	})
+}
+
+func TestContainer_SetSecretKeys_Derived(t *testing.T) {
+	t.Parallel()
+	derivedYaml := redactMockYaml + `
+servers:
+  - host: "a.example.com"
+    password: "letmein"`
+
+	newContainer := func(buf *bytes.Buffer) *config.Container {
+		logger := log.New(buf)
+		logger.SetLevel(log.DebugLevel)
+		c := config.NewReaderContainer(logger, "yaml", strings.NewReader(derivedYaml)).WithReadLogging()
+		c.SetSecretKeys("database.password", "cache.credentials", "servers.password")
+
+		return c
+	}
+
+	t.Run("with Sub", func(t *testing.T) {
+		t.Parallel()
+		buf := &bytes.Buffer{}
+		sub := newContainer(buf).Sub("database")
+
+		assert.Equal(t, "hunter2", sub.GetString("password"))
+		assert.Equal(t, "db.example.com", sub.GetString("host"))
+		assert.NotContains(t, buf.String(), "hunter2")
+		assert.Contains(t, buf.String(), "db.example.com")
+		assert.NotContains(t, sub.ToJSON(), "hunter2")
+		assert.Contains(t, sub.ToJSON(), "db.example.com")
+	})
+
+	t.Run("with SubPath beneath a secret subtree", func(t *testing.T) {
+		t.Parallel()
+		buf := &bytes.Buffer{}
+		sub, err := newContainer(buf).SubPath("cache.credentials")
+		require.NoError(t, err)
+
+		assert.Equal(t, "abc123", sub.GetString("token"))
+		assert.NotContains(t, buf.String(), "abc123")
+		assert.NotContains(t, sub.ToJSON(), "abc123")
+	})
+
+	t.Run("with GetSlice", func(t *testing.T) {
+		t.Parallel()
+		buf := &bytes.Buffer{}
+		servers := newContainer(buf).GetSlice("servers")
+		require.Len(t, servers, 1)
+
+		assert.Equal(t, "letmein", servers[0].GetString("password"))
+		assert.Equal(t, "a.example.com", servers[0].GetString("host"))
+		assert.NotContains(t, buf.String(), "letmein")
+		assert.NotContains(t, servers[0].ToJSON(), "letmein")
+		assert.Contains(t, servers[0].ToJSON(), "a.example.com")
+	})
+}
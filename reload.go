@@ -0,0 +1,219 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/go-errors/errors"
+	"github.com/spf13/afero"
+	"github.com/spf13/viper"
+)
+
+// reloadDebounce window within which bursts of file events are coalesced into a single reload.
+const reloadDebounce = 100 * time.Millisecond
+
+// ReloadHook receives notification of config reloads, e.g. for incrementing metrics counters.
+type ReloadHook interface {
+	OnReload(files []string)
+	OnReloadError(err error)
+}
+
+// SetReloadHook attach a hook invoked whenever watched config files are reloaded or fail to reload.
+func (c *Container) SetReloadHook(h ReloadHook) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.reloadHook = h
+}
+
+// currentReloadHook return the attached reload hook, which may be nil.
+func (c *Container) currentReloadHook() ReloadHook {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.reloadHook
+}
+
+// debounceReload run f once file events have settled, coalescing bursts of events into a single call.
+func (c *Container) debounceReload(f func()) {
+	c.reloadMu.Lock()
+	defer c.reloadMu.Unlock()
+
+	if c.reloadTimer != nil {
+		c.reloadTimer.Stop()
+	}
+	c.reloadTimer = time.AfterFunc(reloadDebounce, f)
+}
+
+// handleConfigChange re-run the full merge of all loaded files and, if it succeeds, notify the hook and,
+// when the content hash shows the settings actually changed, observers. Events that leave the content as
+// it was, such as a touch, don't reach observers.
+func (c *Container) handleConfigChange(e fsnotify.Event) {
+	before := c.changeSnapshot()
+	hash := c.ContentHash()
+	hook := c.currentReloadHook()
+	if err := c.reloadFiles(); err != nil {
+		c.logger.Error("unable to reload config", "file", e.Name, "error", err)
+		if hook != nil {
+			hook.OnReloadError(err)
+		}
+
+		return
+	}
+
+	if hook != nil {
+		hook.OnReload(c.files)
+	}
+
+	if c.ContentHash() == hash {
+		c.logger.Debug("config content unchanged, skipping observers", "file", e.Name)
+
+		return
+	}
+
+	c.logger.Infof("Config updated %v", e)
+
+	c.mu.RLock()
+	eventObservers := c.eventObservers
+	c.mu.RUnlock()
+
+	for _, f := range eventObservers {
+		c.guard("event observer", func() { f(c, e) })
+	}
+	c.publishChange(c.files, before)
+	c.notifyObservers()
+}
+
+// reloadFiles re-read every loaded file, later files merged over earlier ones. All files are read and parsed
+// first, and the result passed to any reload validator, so that a malformed or invalid file leaves the current
+// config untouched. The content that was validated is what is swapped in. Files that no longer exist are
+// skipped, as they are at construction.
+func (c *Container) reloadFiles() error {
+	sources := make([]fileSource, 0, len(c.files))
+
+	for _, f := range c.files {
+		src, err := c.readFileSource(f)
+		var pathError *os.PathError
+		if errors.As(err, &pathError) {
+			continue
+		}
+
+		if err != nil {
+			return errors.Errorf("%s: %w", f, err)
+		}
+
+		sources = append(sources, src)
+	}
+
+	c.mu.RLock()
+	validator := c.reloadValidator
+	c.mu.RUnlock()
+
+	if validator != nil {
+		if err := c.validateReload(validator, sources); err != nil {
+			return err
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	existing := make([]string, 0, len(sources))
+	settings := make([]map[string]interface{}, 0, len(sources))
+	c.raw = nil
+	for _, src := range sources {
+		existing = append(existing, src.path)
+		settings = append(settings, src.settings)
+		c.retainRawContent(src.path, src.content)
+	}
+	c.loadedFiles = existing
+
+	if len(existing) == 0 {
+		return nil
+	}
+
+	c.viper.SetConfigFile(existing[len(existing)-1])
+
+	return c.replaceFileConfig(settings)
+}
+
+// fileSource config file read for a reload, holding both its content and the settings parsed from it.
+type fileSource struct {
+	path     string
+	content  []byte
+	settings map[string]interface{}
+}
+
+// readFileSource read and parse f on its own, without defaults, environment or overrides.
+func (c *Container) readFileSource(f string) (fileSource, error) {
+	b, err := afero.ReadFile(c.fs, f)
+	if err != nil {
+		return fileSource{}, err
+	}
+
+	settings, ok, err := decodeRegistered(f, b)
+	if err != nil {
+		return fileSource{}, err
+	}
+
+	if !ok {
+		format := c.format
+		if format == "" {
+			if format, err = formatFromPath(f); err != nil {
+				return fileSource{}, err
+			}
+		}
+
+		v := viper.New()
+		v.SetConfigType(format)
+		if err := v.ReadConfig(bytes.NewReader(b)); err != nil {
+			return fileSource{}, err
+		}
+		settings = v.AllSettings()
+	}
+
+	return fileSource{path: f, content: b, settings: settings}, nil
+}
+
+// fileSettings read f on its own, returning its config without defaults, environment or overrides.
+func (c *Container) fileSettings(f string) (map[string]interface{}, error) {
+	src, err := c.readFileSource(f)
+
+	return src.settings, err
+}
+
+// SetReloadValidator attach a function that vets reloaded config before it is swapped in. The validator
+// receives the candidate config merged from the changed files, overlaid with environment variables; if it
+// returns an error the current config is kept and the reload hook's OnReloadError is fired.
+func (c *Container) SetReloadValidator(f func(Containable) error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.reloadValidator = f
+}
+
+// validateReload merge sources into a candidate container and run validator against it.
+func (c *Container) validateReload(validator func(Containable) error, sources []fileSource) error {
+	candidate := initContainer(c.logger, c.fs)
+	candidate.ID = fmt.Sprintf("%s@reload", c.ID)
+	if c.envPrefix != "" {
+		candidate.viper.SetEnvPrefix(c.envPrefix)
+	}
+
+	for _, src := range sources {
+		// viper keeps the maps it merges, so the candidate gets copies to leave the sources untouched.
+		settings, _ := copyRaw(src.settings).(map[string]interface{})
+		if err := candidate.viper.MergeConfigMap(settings); err != nil {
+			return errors.Errorf("%s: %w", src.path, err)
+		}
+	}
+
+	if err := validator(candidate); err != nil {
+		return errors.Errorf("reloaded config rejected: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,258 @@
+package config_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/go-errors/errors"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/phpboyscout/config"
+)
+
+type fakeReloadHook struct {
+	mu      sync.Mutex
+	reloads [][]string
+	errs    []error
+}
+
+func (h *fakeReloadHook) OnReload(files []string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.reloads = append(h.reloads, files)
+}
+
+func (h *fakeReloadHook) OnReloadError(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.errs = append(h.errs, err)
+}
+
+func (h *fakeReloadHook) counts() (int, int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return len(h.reloads), len(h.errs)
+}
+
+func TestContainer_SetReloadHook(t *testing.T) {
+	t.Parallel()
+	logger := log.New(io.Discard)
+
+	t.Run("with a successful reload", func(t *testing.T) {
+		t.Parallel()
+		filename := filepath.Join(t.TempDir(), "config.yml")
+		require.NoError(t, os.WriteFile(filename, []byte(firstMockFilesYaml), 0o600))
+
+		c := config.NewFilesContainer(logger, afero.NewOsFs(), filename)
+		hook := &fakeReloadHook{}
+		c.SetReloadHook(hook)
+
+		require.NoError(t, os.WriteFile(filename, []byte(secondMockFilesYaml), 0o600))
+
+		assert.Eventually(t, func() bool {
+			reloads, _ := hook.counts()
+
+			return reloads == 1
+		}, 2*time.Second, 10*time.Millisecond)
+		time.Sleep(300 * time.Millisecond)
+
+		reloads, errs := hook.counts()
+		assert.Equal(t, 1, reloads)
+		assert.Zero(t, errs)
+		assert.Equal(t, []string{filename}, hook.reloads[0])
+	})
+
+	t.Run("with a parse failure", func(t *testing.T) {
+		t.Parallel()
+		filename := filepath.Join(t.TempDir(), "config.yml")
+		require.NoError(t, os.WriteFile(filename, []byte(firstMockFilesYaml), 0o600))
+
+		c := config.NewFilesContainer(logger, afero.NewOsFs(), filename)
+		hook := &fakeReloadHook{}
+		c.SetReloadHook(hook)
+
+		require.NoError(t, os.WriteFile(filename, []byte("yaml: [unclosed"), 0o600))
+
+		assert.Eventually(t, func() bool {
+			_, errs := hook.counts()
+
+			return errs == 1
+		}, 2*time.Second, 10*time.Millisecond)
+
+		reloads, _ := hook.counts()
+		assert.Zero(t, reloads)
+		assert.Equal(t, "value", c.GetString("yaml.key"))
+	})
+}
+
+func TestContainer_SetReloadValidator(t *testing.T) {
+	t.Parallel()
+	logger := log.New(io.Discard)
+
+	validator := func(c config.Containable) error {
+		if c.GetString("yaml.key") == "" {
+			return errors.New("yaml.key is required")
+		}
+
+		return nil
+	}
+
+	t.Run("with an invalid reload", func(t *testing.T) {
+		t.Parallel()
+		filename := filepath.Join(t.TempDir(), "config.yml")
+		require.NoError(t, os.WriteFile(filename, []byte(firstMockFilesYaml), 0o600))
+
+		c := config.NewFilesContainer(logger, afero.NewOsFs(), filename)
+		c.SetReloadValidator(validator)
+		hook := &fakeReloadHook{}
+		c.SetReloadHook(hook)
+
+		require.NoError(t, os.WriteFile(filename, []byte("yaml:\n  bool: false"), 0o600))
+
+		assert.Eventually(t, func() bool {
+			_, errs := hook.counts()
+
+			return errs == 1
+		}, 2*time.Second, 10*time.Millisecond)
+
+		reloads, _ := hook.counts()
+		assert.Zero(t, reloads)
+		assert.Contains(t, hook.errs[0].Error(), "yaml.key is required")
+		assert.Equal(t, "value", c.GetString("yaml.key"))
+		assert.True(t, c.GetBool("yaml.bool"))
+	})
+
+	t.Run("with a valid reload", func(t *testing.T) {
+		t.Parallel()
+		filename := filepath.Join(t.TempDir(), "config.yml")
+		require.NoError(t, os.WriteFile(filename, []byte(firstMockFilesYaml), 0o600))
+
+		c := config.NewFilesContainer(logger, afero.NewOsFs(), filename)
+		c.SetReloadValidator(validator)
+		hook := &fakeReloadHook{}
+		c.SetReloadHook(hook)
+
+		require.NoError(t, os.WriteFile(filename, []byte(secondMockFilesYaml), 0o600))
+
+		assert.Eventually(t, func() bool {
+			reloads, _ := hook.counts()
+
+			return reloads == 1
+		}, 2*time.Second, 10*time.Millisecond)
+		assert.Equal(t, "value2", c.GetString("yaml.key"))
+	})
+
+	t.Run("with the file changing during validation", func(t *testing.T) {
+		t.Parallel()
+		filename := filepath.Join(t.TempDir(), "config.yml")
+		require.NoError(t, os.WriteFile(filename, []byte(firstMockFilesYaml), 0o600))
+
+		c := config.NewFilesContainer(logger, afero.NewOsFs(), filename)
+		var once sync.Once
+		c.SetReloadValidator(func(candidate config.Containable) error {
+			once.Do(func() {
+				require.NoError(t, os.WriteFile(filename, []byte("yaml:\n  bool: false"), 0o600))
+			})
+
+			return validator(candidate)
+		})
+		hook := &fakeReloadHook{}
+		c.SetReloadHook(hook)
+
+		require.NoError(t, os.WriteFile(filename, []byte(secondMockFilesYaml), 0o600))
+
+		assert.Eventually(t, func() bool {
+			reloads, errs := hook.counts()
+
+			return reloads == 1 && errs == 1
+		}, 2*time.Second, 10*time.Millisecond)
+		assert.Equal(t, "value2", c.GetString("yaml.key"))
+	})
+}
+
+func TestContainer_ReloadUnchangedContent(t *testing.T) {
+	t.Parallel()
+	logger := log.New(io.Discard)
+	filename := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(filename, []byte(firstMockFilesYaml), 0o600))
+
+	c := config.NewFilesContainer(logger, afero.NewOsFs(), filename)
+	t.Cleanup(func() { _ = c.Close() })
+	hook := &fakeReloadHook{}
+	c.SetReloadHook(hook)
+	hash := c.ContentHash()
+
+	var observed int32
+	c.AddObserverFunc(func(config.Containable, chan error) {
+		atomic.AddInt32(&observed, 1)
+	})
+
+	require.NoError(t, os.WriteFile(filename, []byte(firstMockFilesYaml), 0o600))
+	assert.Eventually(t, func() bool {
+		reloads, _ := hook.counts()
+
+		return reloads == 1
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, hash, c.ContentHash())
+	assert.Zero(t, atomic.LoadInt32(&observed))
+
+	require.NoError(t, os.WriteFile(filename, []byte(secondMockFilesYaml), 0o600))
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&observed) == 1 }, 2*time.Second, 10*time.Millisecond)
+	assert.NotEqual(t, hash, c.ContentHash())
+}
+
+func TestContainer_SettersDuringReload(t *testing.T) {
+	t.Parallel()
+	logger := log.New(io.Discard)
+	filename := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(filename, []byte(firstMockFilesYaml), 0o600))
+
+	c := config.NewFilesContainer(logger, afero.NewOsFs(), filename)
+	t.Cleanup(func() { _ = c.Close() })
+	c.AddObserverFunc(func(cfg config.Containable, _ chan error) {
+		_ = cfg.GetString("yaml.key")
+		_ = cfg.Get("yaml")
+	})
+
+	hook := &fakeReloadHook{}
+	c.SetReloadHook(hook)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			c.SetSecretKeys("yaml.key")
+			c.SetDecryptor(func(s string) (string, error) { return s, nil })
+			c.WithReadLogging()
+			c.SetReloadHook(hook)
+			c.SetReloadValidator(func(config.Containable) error { return nil })
+			c.AddFinalizer(func(config.Containable) error { return nil })
+			time.Sleep(time.Millisecond)
+		}
+	}()
+
+	require.NoError(t, os.WriteFile(filename, []byte(secondMockFilesYaml), 0o600))
+	assert.Eventually(t, func() bool {
+		reloads, _ := hook.counts()
+
+		return reloads == 1
+	}, 2*time.Second, 10*time.Millisecond)
+	close(stop)
+	<-done
+
+	assert.Equal(t, "value2", c.GetString("yaml.key"))
+}
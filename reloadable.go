@@ -0,0 +1,83 @@
+package config
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Reloadable handle to a single config value that tracks hot-reloads. The value is cached and refreshed by
+// an observer, and when the container is changed with Set, Unset, Reset or BindStdFlags, so Get is a
+// lock-free read. Changes to environment variables aren't seen until the next reload.
+type Reloadable[T any] struct {
+	value atomic.Pointer[T]
+}
+
+// Get return the current value.
+func (r *Reloadable[T]) Get() T {
+	return *r.value.Load()
+}
+
+func (r *Reloadable[T]) store(v T) {
+	r.value.Store(&v)
+}
+
+// ReloadableString reloadable handle to a string value.
+type ReloadableString = Reloadable[string]
+
+// ReloadableInt reloadable handle to an int value.
+type ReloadableInt = Reloadable[int]
+
+// ReloadableBool reloadable handle to a bool value.
+type ReloadableBool = Reloadable[bool]
+
+// ReloadableDuration reloadable handle to a time.Duration value.
+type ReloadableDuration = Reloadable[time.Duration]
+
+// StringValue return a handle whose Get always reflects the current string value of key.
+func (c *Container) StringValue(key string) *ReloadableString {
+	return newReloadable(c, key, Containable.GetString)
+}
+
+// IntValue return a handle whose Get always reflects the current int value of key.
+func (c *Container) IntValue(key string) *ReloadableInt {
+	return newReloadable(c, key, Containable.GetInt)
+}
+
+// BoolValue return a handle whose Get always reflects the current bool value of key.
+func (c *Container) BoolValue(key string) *ReloadableBool {
+	return newReloadable(c, key, Containable.GetBool)
+}
+
+// DurationValue return a handle whose Get always reflects the current duration value of key.
+func (c *Container) DurationValue(key string) *ReloadableDuration {
+	return newReloadable(c, key, Containable.GetDuration)
+}
+
+// newReloadable cache the current value of key and register an observer to refresh it on reload, along
+// with a refresh for changes made directly to the container.
+func newReloadable[T any](c *Container, key string, read func(Containable, string) T) *Reloadable[T] {
+	r := &Reloadable[T]{}
+	r.store(read(c, key))
+
+	c.mu.Lock()
+	c.reloadables = append(c.reloadables, func() { r.store(read(c, key)) })
+	c.mu.Unlock()
+
+	c.AddObserverFunc(func(cc Containable, _ chan error) {
+		r.store(read(cc, key))
+	})
+
+	return r
+}
+
+// refreshReloadables re-read the value of every handle created from the container. It must be called
+// without the container's lock held.
+func (c *Container) refreshReloadables() {
+	c.mu.RLock()
+	refreshes := append([]func(){}, c.reloadables...)
+	c.mu.RUnlock()
+
+	for _, refresh := range refreshes {
+		refresh()
+	}
+}
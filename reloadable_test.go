@@ -0,0 +1,72 @@
+package config_test
+
+import (
+	"context"
+	"flag"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/phpboyscout/config"
+)
+
+func TestContainer_ReloadableValues(t *testing.T) {
+	t.Parallel()
+	l := log.New(io.Discard)
+	c := config.NewReaderContainer(l, "yaml", strings.NewReader(firstMockFilesYaml))
+
+	key := c.StringValue("yaml.key")
+	num := c.IntValue("yaml.int")
+	flag := c.BoolValue("yaml.bool")
+	timeout := c.DurationValue("yaml.duration")
+
+	assert.Equal(t, "value", key.Get())
+	assert.Equal(t, 1, num.Get())
+	assert.True(t, flag.Get())
+	assert.Equal(t, 5*time.Second, timeout.Get())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c.StartPolling(ctx, 10*time.Millisecond, func() (config.Containable, error) {
+		return config.NewReaderContainer(l, "yaml", strings.NewReader(`yaml:
+  key: "reloaded"
+  int: 2
+  bool: false
+  duration: 1m`)), nil
+	})
+
+	assert.Eventually(t, func() bool {
+		return key.Get() == "reloaded" && num.Get() == 2 && !flag.Get() && timeout.Get() == time.Minute
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestContainer_ReloadableValues_Set(t *testing.T) {
+	t.Parallel()
+	l := log.New(io.Discard)
+	c := config.NewReaderContainer(l, "yaml", strings.NewReader(firstMockFilesYaml))
+
+	key := c.StringValue("yaml.key")
+	num := c.IntValue("yaml.int")
+
+	c.Set("yaml.key", "override")
+	c.Set("yaml.int", 7)
+	assert.Equal(t, "override", key.Get())
+	assert.Equal(t, 7, num.Get())
+
+	require.NoError(t, c.Unset("yaml.int"))
+	assert.Equal(t, 1, num.Get())
+
+	require.NoError(t, c.Reset())
+	assert.Equal(t, "value", key.Get())
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("yaml-key", "", "")
+	require.NoError(t, fs.Parse([]string{"-yaml-key=flagged"}))
+	c.BindStdFlags(fs)
+	assert.Equal(t, "flagged", key.Get())
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/charmbracelet/log"
+	"github.com/go-errors/errors"
+)
+
+// RemoteProvider reads raw config from a remote key/value store such as Consul or etcd. Implementations
+// can be stubbed in tests.
+type RemoteProvider interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+}
+
+// LoadRemote Initialise a configuration container from the value held at key in a remote store, parsed in
+// the given format.
+func LoadRemote(ctx context.Context, provider RemoteProvider, key, format string, logger *log.Logger) (Containable, error) {
+	b, err := provider.Get(ctx, key)
+	if err != nil {
+		return nil, errors.WrapPrefix(err, "unable to read remote config "+key, 0)
+	}
+
+	c := NewReaderContainer(logger, format, bytes.NewReader(b))
+	c.ID = key
+	if c.HasLoadErrors() {
+		return nil, errors.Errorf("unable to parse remote config %s: %w", key, errors.Join(c.LoadErrors()...))
+	}
+
+	return c, nil
+}
+
+// RemoteReloader return a reload function for StartPolling that re-fetches key from a remote store, so that
+// remote config can be refreshed periodically. Each fetch is made with ctx, typically the one polling runs
+// under, so that cancelling it also abandons a fetch in flight.
+func RemoteReloader(ctx context.Context, provider RemoteProvider, key, format string, logger *log.Logger) func() (Containable, error) {
+	return func() (Containable, error) {
+		return LoadRemote(ctx, provider, key, format, logger)
+	}
+}
@@ -0,0 +1,83 @@
+package config_test
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/go-errors/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/phpboyscout/config"
+)
+
+type stubRemoteProvider map[string]string
+
+func (s stubRemoteProvider) Get(_ context.Context, key string) ([]byte, error) {
+	v, ok := s[key]
+	if !ok {
+		return nil, errors.Errorf("key %s not found", key)
+	}
+
+	return []byte(v), nil
+}
+
+func TestLoadRemote(t *testing.T) {
+	t.Parallel()
+	logger := log.New(io.Discard)
+	provider := stubRemoteProvider{
+		"app/config": firstMockFilesYaml,
+		"app/broken": "yaml: [unclosed",
+	}
+
+	t.Run("with existing key", func(t *testing.T) {
+		t.Parallel()
+		c, err := config.LoadRemote(context.Background(), provider, "app/config", "yaml", logger)
+		require.NoError(t, err)
+		assert.Equal(t, "value", c.GetString("yaml.key"))
+	})
+
+	t.Run("with missing key", func(t *testing.T) {
+		t.Parallel()
+		_, err := config.LoadRemote(context.Background(), provider, "app/missing", "yaml", logger)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "app/missing")
+	})
+
+	t.Run("with unparseable value", func(t *testing.T) {
+		t.Parallel()
+		_, err := config.LoadRemote(context.Background(), provider, "app/broken", "yaml", logger)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "app/broken")
+	})
+}
+
+type switchingRemoteProvider struct {
+	value atomic.Value
+}
+
+func (s *switchingRemoteProvider) Get(context.Context, string) ([]byte, error) {
+	return []byte(s.value.Load().(string)), nil
+}
+
+func TestRemoteReloader(t *testing.T) {
+	t.Parallel()
+	logger := log.New(io.Discard)
+	provider := &switchingRemoteProvider{}
+	provider.value.Store(firstMockFilesYaml)
+
+	c, err := config.LoadRemote(context.Background(), provider, "app/config", "yaml", logger)
+	require.NoError(t, err)
+	require.Equal(t, "value", c.GetString("yaml.key"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c.(*config.Container).StartPolling(ctx, 10*time.Millisecond, config.RemoteReloader(ctx, provider, "app/config", "yaml", logger))
+
+	provider.value.Store(secondMockFilesYaml)
+	assert.Eventually(t, func() bool { return c.GetString("yaml.key") == "value2" }, time.Second, 5*time.Millisecond)
+}
@@ -0,0 +1,617 @@
+package config
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"net"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-errors/errors"
+	"github.com/spf13/cast"
+)
+
+// ErrKeyNotSet returned by validating accessors when the requested key has no value.
+var ErrKeyNotSet = errors.New("config key is not set")
+
+// ErrOutOfRange returned by sized numeric accessors when a value doesn't fit the requested type.
+var ErrOutOfRange = errors.New("config value out of range")
+
+// ErrTypeMismatch returned by strict accessors when a value was written as a different type, e.g. an
+// unquoted number where a string was expected.
+var ErrTypeMismatch = errors.New("config value has the wrong type")
+
+// byteUnits multipliers for the size suffixes accepted by GetBytes, keyed by lower-cased unit.
+var byteUnits = map[string]float64{
+	"":    1,
+	"b":   1,
+	"kb":  1e3,
+	"mb":  1e6,
+	"gb":  1e9,
+	"tb":  1e12,
+	"pb":  1e15,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+	"pib": 1 << 50,
+}
+
+// GetURL get string value from config parsed as a URL.
+func (c *Container) GetURL(key string) (*url.URL, error) {
+	s, err := c.requireString(key)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, errors.Errorf("config key %q is not a valid URL: %w", key, err)
+	}
+
+	return u, nil
+}
+
+// MustGetURL get URL value from config, panicking if it is missing or malformed.
+func (c *Container) MustGetURL(key string) *url.URL {
+	u, err := c.GetURL(key)
+	if err != nil {
+		panic(err)
+	}
+
+	return u
+}
+
+// GetIP get string value from config parsed as an IP address.
+func (c *Container) GetIP(key string) (net.IP, error) {
+	s, err := c.requireString(key)
+	if err != nil {
+		return nil, err
+	}
+
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, errors.Errorf("config key %q is not a valid IP address: %q", key, s)
+	}
+
+	return ip, nil
+}
+
+// GetCIDR get string value from config parsed as a CIDR network.
+func (c *Container) GetCIDR(key string) (*net.IPNet, error) {
+	s, err := c.requireString(key)
+	if err != nil {
+		return nil, err
+	}
+
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		return nil, errors.Errorf("config key %q is not a valid CIDR network: %w", key, err)
+	}
+
+	return n, nil
+}
+
+// GetBytes get size value from config in bytes, parsing human-readable suffixes such as "64MB" or "1.5GiB".
+// Decimal units (KB, MB, ...) use multiples of 1000 and binary units (KiB, MiB, ...) multiples of 1024.
+func (c *Container) GetBytes(key string) (int64, error) {
+	s, err := c.requireString(key)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := parseBytes(s)
+	if err != nil {
+		return 0, errors.Errorf("config key %q is not a valid size: %w", key, err)
+	}
+
+	return n, nil
+}
+
+// parseBytes convert a size string with an optional unit suffix into bytes.
+func parseBytes(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i == -1 {
+		i = len(s)
+	}
+
+	num, unit := s[:i], strings.ToLower(strings.TrimSpace(s[i:]))
+
+	multiplier, ok := byteUnits[unit]
+	if !ok {
+		return 0, errors.Errorf("unknown size unit %q", s[i:])
+	}
+
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, errors.Errorf("invalid size %q", s)
+	}
+
+	bytes := f * multiplier
+	if bytes >= math.MaxInt64 {
+		return 0, errors.Errorf("size %q overflows int64", s)
+	}
+
+	return int64(bytes), nil
+}
+
+// GetTimeInLayout get time value from config parsed with the supplied layout.
+// Unlike GetTime an unparseable value is reported as an error rather than a zero time.
+func (c *Container) GetTimeInLayout(key, layout string) (time.Time, error) {
+	s, err := c.requireString(key)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	t, err := time.Parse(layout, s)
+	if err != nil {
+		return time.Time{}, errors.Errorf("config key %q does not match layout %q: %w", key, layout, err)
+	}
+
+	return t, nil
+}
+
+// offsetTimeLayouts layouts for timestamps that carry their own zone offset.
+var offsetTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05.999999999 -0700 MST",
+}
+
+// naiveTimeLayouts layouts for timestamps without a zone offset.
+var naiveTimeLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// GetTimeIn get time value from config converted to loc. Timestamps with an explicit offset are converted
+// to loc, while naive timestamps without one are interpreted as being in loc.
+func (c *Container) GetTimeIn(key string, loc *time.Location) (time.Time, error) {
+	s, err := c.requireString(key)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	for _, layout := range offsetTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.In(loc), nil
+		}
+	}
+
+	for _, layout := range naiveTimeLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, errors.Errorf("config key %q is not a recognised timestamp: %q", key, s)
+}
+
+// GetTimeUTC get time value from config in UTC, as GetTimeIn.
+func (c *Container) GetTimeUTC(key string) (time.Time, error) {
+	return c.GetTimeIn(key, time.UTC)
+}
+
+// GetPath get file path value from config. Relative paths are resolved against the directory of the primary
+// config file rather than the working directory; absolute paths, and paths from containers not loaded from
+// files, are returned as is.
+func (c *Container) GetPath(key string) string {
+	p := c.GetString(key)
+	if p == "" || filepath.IsAbs(p) || c.baseDir == "" {
+		return p
+	}
+
+	return filepath.Join(c.baseDir, p)
+}
+
+// GetStringFirst get the string value of the first of keys that is set, along with the key that matched,
+// e.g. to prefer a new key over a deprecated one. Empty strings are returned if none is set.
+func (c *Container) GetStringFirst(keys ...string) (value string, foundKey string) {
+	for _, k := range keys {
+		if c.IsSet(k) {
+			return c.GetString(k), k
+		}
+	}
+
+	return "", ""
+}
+
+// GetMapKeys get the sorted keys of the map at key, e.g. for feature sets written as
+// `enabled_features: {auth: true}`. An empty slice is returned if key is missing or not a map.
+func (c *Container) GetMapKeys(key string) []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	m := c.viper.GetStringMap(c.lookupKey(key))
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return logged(c, key, keys)
+}
+
+// GetJSON get the value or subtree at key re-encoded as JSON, for forwarding opaque config blocks verbatim.
+func (c *Container) GetJSON(key string) (json.RawMessage, error) {
+	if !c.IsSet(key) {
+		return nil, errors.Errorf("%w: %s", ErrKeyNotSet, key)
+	}
+
+	b, err := json.Marshal(c.Get(key))
+	if err != nil {
+		return nil, errors.Errorf("unable to encode config key %q as JSON: %w", key, err)
+	}
+
+	return b, nil
+}
+
+// GetEnum get string value from config, erroring if it is not one of the allowed values.
+func (c *Container) GetEnum(key string, allowed []string) (string, error) {
+	return c.getEnum(key, allowed, false)
+}
+
+// GetEnumFold get string value from config as GetEnum, comparing case-insensitively and returning the
+// matching allowed value in its canonical case.
+func (c *Container) GetEnumFold(key string, allowed []string) (string, error) {
+	return c.getEnum(key, allowed, true)
+}
+
+func (c *Container) getEnum(key string, allowed []string, fold bool) (string, error) {
+	s, err := c.requireString(key)
+	if err != nil {
+		return "", err
+	}
+
+	for _, a := range allowed {
+		if s == a || (fold && strings.EqualFold(s, a)) {
+			return a, nil
+		}
+	}
+
+	return "", errors.Errorf("config key %q has invalid value %q, must be one of: %s", key, s, strings.Join(allowed, ", "))
+}
+
+// GetRune get the first character of a string value from config, e.g. a CSV delimiter. Zero is returned
+// when the value is empty; supply a different fallback through LoadOptions.Defaults.
+func (c *Container) GetRune(key string) rune {
+	for _, r := range c.GetString(key) {
+		return r
+	}
+
+	return 0
+}
+
+// GetRuneStrict get a single-character string value from config, erroring if it is missing, empty or
+// holds more than one character.
+func (c *Container) GetRuneStrict(key string) (rune, error) {
+	s, err := c.requireString(key)
+	if err != nil {
+		return 0, err
+	}
+
+	if utf8.RuneCountInString(s) != 1 {
+		return 0, errors.Errorf("config key %q must be a single character, got %q", key, s)
+	}
+
+	r, _ := utf8.DecodeRuneInString(s)
+
+	return r, nil
+}
+
+// GetByte get a single ASCII character value from config, erroring if it is missing, empty, longer than one
+// character or not ASCII.
+func (c *Container) GetByte(key string) (byte, error) {
+	r, err := c.GetRuneStrict(key)
+	if err != nil {
+		return 0, err
+	}
+
+	if r >= utf8.RuneSelf {
+		return 0, errors.Errorf("config key %q must be an ASCII character, got %q", key, r)
+	}
+
+	return byte(r), nil
+}
+
+// GetStringStrict get string value from config, erroring if it is missing or was not written as a string,
+// rather than coercing it as GetString does. This catches values such as `version: 1.0` that YAML reads as
+// a number when a string was meant.
+func (c *Container) GetStringStrict(key string) (string, error) {
+	v, err := c.requireValue(key)
+	if err != nil {
+		return "", err
+	}
+
+	str, ok := v.(string)
+	if !ok {
+		return "", errors.Errorf("%w: config key %q must be a string, got %T %v", ErrTypeMismatch, key, v, v)
+	}
+
+	return str, nil
+}
+
+// GetIntStrict get int value from config, erroring if it is missing or was not written as an integer,
+// rather than coercing floats or strings as GetInt does. Values from environment variables, which are
+// always text, are accepted when they hold an integer.
+func (c *Container) GetIntStrict(key string) (int, error) {
+	v, err := c.requireValue(key)
+	if err != nil {
+		return 0, err
+	}
+
+	switch t := v.(type) {
+	case int, int8, int16, int32, uint8, uint16:
+		return cast.ToInt(t), nil
+	case int64, uint32, uint, uint64:
+		if n, err := c.getSigned(key, strconv.IntSize); err == nil {
+			return int(n), nil
+		}
+
+		return 0, errors.Errorf("%w: config key %q value %v overflows int", ErrOutOfRange, key, t)
+	case string:
+		if c.Explain(key).Source == SourceEnv {
+			n, err := strconv.Atoi(strings.TrimSpace(t))
+			if err != nil {
+				return 0, errors.Errorf("%w: config key %q must be an integer, got %q", ErrTypeMismatch, key, t)
+			}
+
+			return n, nil
+		}
+	}
+
+	return 0, errors.Errorf("%w: config key %q must be an integer, got %T %v", ErrTypeMismatch, key, v, v)
+}
+
+// GetInt64Strict get int64 value from config, erroring if it is missing, not an integer or out of range.
+func (c *Container) GetInt64Strict(key string) (int64, error) {
+	return c.getSigned(key, 64)
+}
+
+// GetInt32Strict get int32 value from config, erroring if it is missing, not an integer or out of range.
+func (c *Container) GetInt32Strict(key string) (int32, error) {
+	n, err := c.getSigned(key, 32)
+
+	return int32(n), err
+}
+
+// GetUintStrict get uint value from config, erroring if it is missing, not an integer, negative or out of
+// range.
+func (c *Container) GetUintStrict(key string) (uint, error) {
+	n, err := c.getUnsigned(key, strconv.IntSize)
+
+	return uint(n), err
+}
+
+// GetUint64Strict get uint64 value from config, erroring if it is missing, not an integer or negative.
+func (c *Container) GetUint64Strict(key string) (uint64, error) {
+	return c.getUnsigned(key, 64)
+}
+
+// GetFloat32Strict get float32 value from config, erroring if it is missing, not a number or out of range.
+func (c *Container) GetFloat32Strict(key string) (float32, error) {
+	v, err := c.requireValue(key)
+	if err != nil {
+		return 0, err
+	}
+
+	f, err := cast.ToFloat64E(v)
+	if err != nil {
+		return 0, errors.Errorf("config key %q is not a valid number: %w", key, err)
+	}
+
+	if math.Abs(f) > math.MaxFloat32 && !math.IsInf(f, 0) {
+		return 0, errors.Errorf("%w: config key %q value %v overflows float32", ErrOutOfRange, key, f)
+	}
+
+	return float32(f), nil
+}
+
+// getSigned read key as a signed integer that must fit in bits.
+func (c *Container) getSigned(key string, bits int) (int64, error) {
+	v, err := c.requireValue(key)
+	if err != nil {
+		return 0, err
+	}
+
+	var n int64
+	switch t := v.(type) {
+	case uint64:
+		if t > math.MaxInt64 {
+			return 0, errors.Errorf("%w: config key %q value %d overflows int%d", ErrOutOfRange, key, t, bits)
+		}
+		n = int64(t)
+	case float64:
+		if t < math.MinInt64 || t >= math.MaxInt64 {
+			return 0, errors.Errorf("%w: config key %q value %v overflows int%d", ErrOutOfRange, key, t, bits)
+		}
+		if t != math.Trunc(t) {
+			return 0, errors.Errorf("config key %q value %v is not a whole number", key, t)
+		}
+		n = int64(t)
+	case string:
+		n, err = strconv.ParseInt(strings.TrimSpace(t), 0, 64)
+		if errors.Is(err, strconv.ErrRange) {
+			return 0, errors.Errorf("%w: config key %q value %s overflows int%d", ErrOutOfRange, key, t, bits)
+		}
+	default:
+		n, err = cast.ToInt64E(t)
+	}
+
+	if err != nil {
+		return 0, errors.Errorf("config key %q is not a valid integer: %w", key, err)
+	}
+
+	if bits < 64 && (n < -1<<(bits-1) || n > 1<<(bits-1)-1) {
+		return 0, errors.Errorf("%w: config key %q value %d overflows int%d", ErrOutOfRange, key, n, bits)
+	}
+
+	return n, nil
+}
+
+// getUnsigned read key as a non-negative integer that must fit in bits.
+func (c *Container) getUnsigned(key string, bits int) (uint64, error) {
+	v, err := c.requireValue(key)
+	if err != nil {
+		return 0, err
+	}
+
+	var n uint64
+	switch t := v.(type) {
+	case float64:
+		// 2^64 is exactly representable, whereas math.MaxUint64 rounds up to it as a float64.
+		if t < 0 || t >= 1<<64 {
+			return 0, errors.Errorf("%w: config key %q value %v overflows uint%d", ErrOutOfRange, key, t, bits)
+		}
+		if t != math.Trunc(t) {
+			return 0, errors.Errorf("config key %q value %v is not a whole number", key, t)
+		}
+		n = uint64(t)
+	case string:
+		n, err = strconv.ParseUint(strings.TrimSpace(t), 0, 64)
+		if errors.Is(err, strconv.ErrRange) {
+			return 0, errors.Errorf("%w: config key %q value %s overflows uint%d", ErrOutOfRange, key, t, bits)
+		}
+	default:
+		n, err = cast.ToUint64E(t)
+	}
+
+	if err != nil {
+		return 0, errors.Errorf("config key %q is not a valid unsigned integer: %w", key, err)
+	}
+
+	if bits < 64 && n > 1<<bits-1 {
+		return 0, errors.Errorf("%w: config key %q value %d overflows uint%d", ErrOutOfRange, key, n, bits)
+	}
+
+	return n, nil
+}
+
+// GetBytesBase64 get binary value from config, such as an embedded certificate or key, decoded from
+// standard base64.
+func (c *Container) GetBytesBase64(key string) ([]byte, error) {
+	return c.getBase64(key, base64.StdEncoding)
+}
+
+// GetBytesBase64URL get binary value from config as GetBytesBase64, decoded from URL-safe base64. Padding is
+// optional.
+func (c *Container) GetBytesBase64URL(key string) ([]byte, error) {
+	return c.getBase64(key, base64.RawURLEncoding)
+}
+
+func (c *Container) getBase64(key string, enc *base64.Encoding) ([]byte, error) {
+	s, err := c.requireString(key)
+	if err != nil {
+		return nil, err
+	}
+
+	s = strings.TrimSpace(s)
+	if enc == base64.RawURLEncoding {
+		s = strings.TrimRight(s, "=")
+	}
+
+	b, err := enc.DecodeString(s)
+	if err != nil {
+		return nil, errors.Errorf("config key %q is not valid base64: %w", key, err)
+	}
+
+	return b, nil
+}
+
+// GetStringSliceCSV get string slice value from config, splitting a single string value such as one from
+// an environment variable on commas. Native lists are returned as is.
+func (c *Container) GetStringSliceCSV(key string) []string {
+	return c.GetStringSliceSep(key, ",")
+}
+
+// GetStringSliceSep get string slice value from config as GetStringSliceCSV, splitting on sep. Elements are
+// trimmed of whitespace and empty elements dropped.
+func (c *Container) GetStringSliceSep(key, sep string) []string {
+	s, ok := c.Get(key).(string)
+	if !ok {
+		return c.GetStringSlice(key)
+	}
+
+	values := make([]string, 0)
+	for _, v := range strings.Split(s, sep) {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.decryptSlice(key, values)
+}
+
+// GetDurationSlice get a list of durations from config, such as a retry backoff schedule, parsing each
+// element with time.ParseDuration. A single string value, e.g. from an environment variable, is split on
+// commas. The first malformed element is reported with its index.
+func (c *Container) GetDurationSlice(key string) ([]time.Duration, error) {
+	if !c.IsSet(key) {
+		return nil, errors.Errorf("%w: %s", ErrKeyNotSet, key)
+	}
+
+	values := c.GetStringSliceCSV(key)
+	durations := make([]time.Duration, len(values))
+	for i, v := range values {
+		d, err := time.ParseDuration(strings.TrimSpace(v))
+		if err != nil {
+			return nil, errors.Errorf("config key %q element %d is not a valid duration: %w", key, i, err)
+		}
+		durations[i] = d
+	}
+
+	return durations, nil
+}
+
+// requireValue retrieve the raw value for key, erroring if it has not been set.
+func (c *Container) requireValue(key string) (interface{}, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if !c.viper.IsSet(c.lookupKey(key)) {
+		return nil, errors.Errorf("%w: %s", ErrKeyNotSet, key)
+	}
+
+	v := c.viper.Get(c.lookupKey(key))
+	if s, ok := v.(string); ok {
+		plain, err := c.decrypt(s)
+		if err != nil {
+			return nil, errors.Errorf("unable to decrypt config key %q: %w", key, err)
+		}
+
+		return plain, nil
+	}
+
+	return c.decryptValue(key, v), nil
+}
+
+// requireString retrieve the string value for key, erroring if it has not been set.
+func (c *Container) requireString(key string) (string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if !c.viper.IsSet(c.lookupKey(key)) {
+		return "", errors.Errorf("%w: %s", ErrKeyNotSet, key)
+	}
+
+	s, err := c.decrypt(c.viper.GetString(c.lookupKey(key)))
+	if err != nil {
+		return "", errors.Errorf("unable to decrypt config key %q: %w", key, err)
+	}
+
+	return s, nil
+}
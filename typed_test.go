@@ -0,0 +1,751 @@
+package config_test
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/phpboyscout/config"
+)
+
+var typedMockYaml = `typed:
+  url: "postgres://user@db.example.com:5432/app?sslmode=disable"
+  badurl: "http://[::1"
+  ipv4: "192.168.1.10"
+  ipv6: "2001:db8::1"
+  badip: "300.1.1.1"
+  cidr4: "10.0.0.0/8"
+  cidr6: "2001:db8::/32"
+  badcidr: "10.0.0.0/33"
+  bytes:
+    bare: 512
+    b: "512B"
+    kb: "2KB"
+    kib: "2KiB"
+    mb: "64MB"
+    mib: "64 MiB"
+    gb: "1.5GB"
+    gib: "1gib"
+    unknown: "10XB"
+    overflow: "8192PiB"
+    invalid: "lots"
+  date: "11/09/2021"
+  times:
+    utc: "2024-03-01T12:00:00Z"
+    offset: "2024-03-01T12:00:00+02:00"
+    naive: "2024-03-01 12:00:00"
+    dateonly: "2024-03-01"
+    bad: "yesterday"
+  level: "warn"
+  shouty: "ERROR"
+  delimiter: ","
+  tab: "\t"
+  emptydelim: ""
+  multi: "||"
+  features:
+    - auth
+    - logging
+    - metrics
+  unicode: "§"`
+
+func TestContainer_GetURL(t *testing.T) {
+	t.Parallel()
+	l := log.New(io.Discard)
+	c := config.NewReaderContainer(l, "yaml", strings.NewReader(typedMockYaml))
+
+	t.Run("with valid url", func(t *testing.T) {
+		t.Parallel()
+		u, err := c.GetURL("typed.url")
+		require.NoError(t, err)
+		assert.Equal(t, "postgres", u.Scheme)
+		assert.Equal(t, "db.example.com:5432", u.Host)
+		assert.Equal(t, "/app", u.Path)
+		assert.Equal(t, "disable", u.Query().Get("sslmode"))
+	})
+
+	t.Run("with missing key", func(t *testing.T) {
+		t.Parallel()
+		_, err := c.GetURL("typed.missing")
+		require.ErrorIs(t, err, config.ErrKeyNotSet)
+	})
+
+	t.Run("with malformed url", func(t *testing.T) {
+		t.Parallel()
+		_, err := c.GetURL("typed.badurl")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "typed.badurl")
+	})
+
+	t.Run("test MustGetURL", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "db.example.com:5432", c.MustGetURL("typed.url").Host)
+		assert.Panics(t, func() { c.MustGetURL("typed.badurl") })
+	})
+}
+
+func TestContainer_GetIP(t *testing.T) {
+	t.Parallel()
+	l := log.New(io.Discard)
+	c := config.NewReaderContainer(l, "yaml", strings.NewReader(typedMockYaml))
+
+	t.Run("with IPv4", func(t *testing.T) {
+		t.Parallel()
+		ip, err := c.GetIP("typed.ipv4")
+		require.NoError(t, err)
+		assert.Equal(t, "192.168.1.10", ip.String())
+		assert.NotNil(t, ip.To4())
+	})
+
+	t.Run("with IPv6", func(t *testing.T) {
+		t.Parallel()
+		ip, err := c.GetIP("typed.ipv6")
+		require.NoError(t, err)
+		assert.Equal(t, "2001:db8::1", ip.String())
+		assert.Nil(t, ip.To4())
+	})
+
+	t.Run("with invalid input", func(t *testing.T) {
+		t.Parallel()
+		_, err := c.GetIP("typed.badip")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "300.1.1.1")
+	})
+
+	t.Run("with missing key", func(t *testing.T) {
+		t.Parallel()
+		_, err := c.GetIP("typed.missing")
+		require.ErrorIs(t, err, config.ErrKeyNotSet)
+	})
+}
+
+func TestContainer_GetCIDR(t *testing.T) {
+	t.Parallel()
+	l := log.New(io.Discard)
+	c := config.NewReaderContainer(l, "yaml", strings.NewReader(typedMockYaml))
+
+	t.Run("with IPv4", func(t *testing.T) {
+		t.Parallel()
+		n, err := c.GetCIDR("typed.cidr4")
+		require.NoError(t, err)
+		assert.Equal(t, "10.0.0.0/8", n.String())
+	})
+
+	t.Run("with IPv6", func(t *testing.T) {
+		t.Parallel()
+		n, err := c.GetCIDR("typed.cidr6")
+		require.NoError(t, err)
+		assert.Equal(t, "2001:db8::/32", n.String())
+	})
+
+	t.Run("with invalid input", func(t *testing.T) {
+		t.Parallel()
+		_, err := c.GetCIDR("typed.badcidr")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "typed.badcidr")
+	})
+
+	t.Run("with missing key", func(t *testing.T) {
+		t.Parallel()
+		_, err := c.GetCIDR("typed.missing")
+		require.ErrorIs(t, err, config.ErrKeyNotSet)
+	})
+}
+
+func TestContainer_GetBytes(t *testing.T) {
+	t.Parallel()
+	l := log.New(io.Discard)
+	c := config.NewReaderContainer(l, "yaml", strings.NewReader(typedMockYaml))
+
+	for key, expected := range map[string]int64{
+		"typed.bytes.bare": 512,
+		"typed.bytes.b":    512,
+		"typed.bytes.kb":   2000,
+		"typed.bytes.kib":  2048,
+		"typed.bytes.mb":   64_000_000,
+		"typed.bytes.mib":  64 << 20,
+		"typed.bytes.gb":   1_500_000_000,
+		"typed.bytes.gib":  1 << 30,
+	} {
+		key, expected := key, expected
+		t.Run("with "+key, func(t *testing.T) {
+			t.Parallel()
+			n, err := c.GetBytes(key)
+			require.NoError(t, err)
+			assert.Equal(t, expected, n)
+		})
+	}
+
+	t.Run("with unknown unit", func(t *testing.T) {
+		t.Parallel()
+		_, err := c.GetBytes("typed.bytes.unknown")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "XB")
+	})
+
+	t.Run("with invalid string", func(t *testing.T) {
+		t.Parallel()
+		_, err := c.GetBytes("typed.bytes.invalid")
+		require.Error(t, err)
+	})
+
+	t.Run("with overflow", func(t *testing.T) {
+		t.Parallel()
+		_, err := c.GetBytes("typed.bytes.overflow")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "overflows int64")
+	})
+
+	t.Run("with missing key", func(t *testing.T) {
+		t.Parallel()
+		_, err := c.GetBytes("typed.bytes.missing")
+		require.ErrorIs(t, err, config.ErrKeyNotSet)
+	})
+}
+
+func TestContainer_GetTimeInLayout(t *testing.T) {
+	t.Parallel()
+	l := log.New(io.Discard)
+	c := config.NewReaderContainer(l, "yaml", strings.NewReader(typedMockYaml))
+
+	t.Run("with custom layout", func(t *testing.T) {
+		t.Parallel()
+		v, err := c.GetTimeInLayout("typed.date", "02/01/2006")
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2021, time.September, 11, 0, 0, 0, 0, time.UTC), v)
+		assert.True(t, c.GetTime("typed.date").IsZero())
+	})
+
+	t.Run("with unparseable value", func(t *testing.T) {
+		t.Parallel()
+		v, err := c.GetTimeInLayout("typed.date", time.RFC3339)
+		require.Error(t, err)
+		assert.True(t, v.IsZero())
+	})
+
+	t.Run("with missing key", func(t *testing.T) {
+		t.Parallel()
+		_, err := c.GetTimeInLayout("typed.missing", time.RFC3339)
+		require.ErrorIs(t, err, config.ErrKeyNotSet)
+	})
+}
+
+func TestContainer_GetEnum(t *testing.T) {
+	t.Parallel()
+	l := log.New(io.Discard)
+	c := config.NewReaderContainer(l, "yaml", strings.NewReader(typedMockYaml))
+	levels := []string{"info", "warn", "error"}
+
+	t.Run("with valid value", func(t *testing.T) {
+		t.Parallel()
+		v, err := c.GetEnum("typed.level", levels)
+		require.NoError(t, err)
+		assert.Equal(t, "warn", v)
+	})
+
+	t.Run("with invalid value", func(t *testing.T) {
+		t.Parallel()
+		_, err := c.GetEnum("typed.shouty", levels)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "info, warn, error")
+	})
+
+	t.Run("with case-insensitive match", func(t *testing.T) {
+		t.Parallel()
+		v, err := c.GetEnumFold("typed.shouty", levels)
+		require.NoError(t, err)
+		assert.Equal(t, "error", v)
+	})
+
+	t.Run("with missing key", func(t *testing.T) {
+		t.Parallel()
+		_, err := c.GetEnum("typed.missing", levels)
+		require.ErrorIs(t, err, config.ErrKeyNotSet)
+	})
+
+	t.Run("with missing key and default", func(t *testing.T) {
+		t.Parallel()
+		d := config.NewReaderContainer(l, "yaml", strings.NewReader(typedMockYaml))
+		d.GetViper().SetDefault("typed.missing", "info")
+
+		v, err := d.GetEnum("typed.missing", levels)
+		require.NoError(t, err)
+		assert.Equal(t, "info", v)
+	})
+}
+
+func TestContainer_GetRune(t *testing.T) {
+	t.Parallel()
+	l := log.New(io.Discard)
+	c := config.NewReaderContainer(l, "yaml", strings.NewReader(typedMockYaml))
+
+	t.Run("with single character", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, ',', c.GetRune("typed.delimiter"))
+		assert.Equal(t, '\t', c.GetRune("typed.tab"))
+
+		r, err := c.GetRuneStrict("typed.unicode")
+		require.NoError(t, err)
+		assert.Equal(t, '§', r)
+	})
+
+	t.Run("with empty value", func(t *testing.T) {
+		t.Parallel()
+		assert.Zero(t, c.GetRune("typed.emptydelim"))
+
+		_, err := c.GetRuneStrict("typed.emptydelim")
+		require.Error(t, err)
+
+		_, err = c.GetRuneStrict("typed.missing")
+		require.ErrorIs(t, err, config.ErrKeyNotSet)
+	})
+
+	t.Run("with default", func(t *testing.T) {
+		t.Parallel()
+		d := config.NewReaderContainer(l, "yaml", strings.NewReader(typedMockYaml))
+		d.GetViper().SetDefault("typed.quote", `"`)
+		assert.Equal(t, '"', d.GetRune("typed.quote"))
+	})
+
+	t.Run("with multiple characters", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, '|', c.GetRune("typed.multi"))
+
+		_, err := c.GetRuneStrict("typed.multi")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "single character")
+	})
+}
+
+func TestContainer_GetByte(t *testing.T) {
+	t.Parallel()
+	l := log.New(io.Discard)
+	c := config.NewReaderContainer(l, "yaml", strings.NewReader(typedMockYaml))
+
+	b, err := c.GetByte("typed.delimiter")
+	require.NoError(t, err)
+	assert.Equal(t, byte(','), b)
+
+	_, err = c.GetByte("typed.unicode")
+	require.Error(t, err)
+
+	_, err = c.GetByte("typed.multi")
+	require.Error(t, err)
+}
+
+func TestContainer_GetStringSliceCSV(t *testing.T) {
+	t.Setenv("CSV_FEATURES", "auth, logging,metrics,")
+	t.Setenv("CSV_PIPED", "auth|logging | metrics")
+
+	l := log.New(io.Discard)
+	c := config.NewReaderContainer(l, "yaml", strings.NewReader(typedMockYaml))
+	expected := []string{"auth", "logging", "metrics"}
+
+	assert.Equal(t, expected, c.GetStringSliceCSV("typed.features"))
+	assert.Equal(t, expected, c.GetStringSliceCSV("csv.features"))
+	assert.Equal(t, expected, c.GetStringSliceSep("csv.piped", "|"))
+	assert.Empty(t, c.GetStringSliceCSV("csv.missing"))
+}
+
+func TestContainer_GetTimeIn(t *testing.T) {
+	t.Parallel()
+	l := log.New(io.Discard)
+	c := config.NewReaderContainer(l, "yaml", strings.NewReader(typedMockYaml))
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	t.Run("with UTC value", func(t *testing.T) {
+		t.Parallel()
+		v, err := c.GetTimeUTC("typed.times.utc")
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC), v)
+		assert.Equal(t, time.UTC, v.Location())
+	})
+
+	t.Run("with offset value", func(t *testing.T) {
+		t.Parallel()
+		v, err := c.GetTimeIn("typed.times.offset", ny)
+		require.NoError(t, err)
+		assert.Equal(t, ny, v.Location())
+		assert.True(t, v.Equal(time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("with naive value", func(t *testing.T) {
+		t.Parallel()
+		v, err := c.GetTimeIn("typed.times.naive", ny)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 3, 1, 12, 0, 0, 0, ny), v)
+
+		v, err = c.GetTimeIn("typed.times.dateonly", ny)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, ny), v)
+	})
+
+	t.Run("with invalid value", func(t *testing.T) {
+		t.Parallel()
+		_, err := c.GetTimeUTC("typed.times.bad")
+		require.Error(t, err)
+
+		_, err = c.GetTimeUTC("typed.times.missing")
+		require.ErrorIs(t, err, config.ErrKeyNotSet)
+	})
+}
+
+func TestContainer_GetPath(t *testing.T) {
+	t.Parallel()
+	l := log.New(io.Discard)
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/etc/app/config.yml", []byte(`tls:
+  cert: ./certs/tls.pem
+  key: /var/lib/app/tls.key
+  ca: ../shared/ca.pem`), 0o644))
+
+	c := config.NewFilesContainer(l, fs, "/etc/app/config.yml")
+
+	assert.Equal(t, "/etc/app/certs/tls.pem", c.GetPath("tls.cert"))
+	assert.Equal(t, "/var/lib/app/tls.key", c.GetPath("tls.key"))
+	assert.Equal(t, "/etc/shared/ca.pem", c.GetPath("tls.ca"))
+	assert.Empty(t, c.GetPath("tls.missing"))
+
+	assert.Equal(t, "/etc/app/certs/tls.pem", c.Sub("tls").(*config.Container).GetPath("cert"))
+	assert.Equal(t, "/etc/app/certs/tls.pem", c.Clone().GetPath("tls.cert"))
+
+	r := config.NewReaderContainer(l, "yaml", strings.NewReader("tls:\n  cert: ./certs/tls.pem"))
+	assert.Equal(t, "./certs/tls.pem", r.GetPath("tls.cert"))
+}
+
+func TestContainer_GetDurationSlice(t *testing.T) {
+	l := log.New(io.Discard)
+	c := config.NewReaderContainer(l, "yaml", strings.NewReader(`retry:
+  backoffs: [1s, 5s, 30s]
+  broken: [1s, soon, 30s]`))
+
+	t.Run("with a valid list", func(t *testing.T) {
+		d, err := c.GetDurationSlice("retry.backoffs")
+		require.NoError(t, err)
+		assert.Equal(t, []time.Duration{time.Second, 5 * time.Second, 30 * time.Second}, d)
+	})
+
+	t.Run("with a malformed element", func(t *testing.T) {
+		_, err := c.GetDurationSlice("retry.broken")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "element 1")
+	})
+
+	t.Run("with a missing key", func(t *testing.T) {
+		_, err := c.GetDurationSlice("retry.missing")
+		require.ErrorIs(t, err, config.ErrKeyNotSet)
+	})
+
+	t.Run("with a comma separated env value", func(t *testing.T) {
+		t.Setenv("RETRY_SCHEDULE", "100ms, 2s,1m")
+
+		d, err := c.GetDurationSlice("retry.schedule")
+		require.NoError(t, err)
+		assert.Equal(t, []time.Duration{100 * time.Millisecond, 2 * time.Second, time.Minute}, d)
+	})
+}
+
+func TestContainer_SizedNumbers(t *testing.T) {
+	t.Parallel()
+	l := log.New(io.Discard)
+	c := config.NewReaderContainer(l, "yaml", strings.NewReader(`limits:
+  small: 1024
+  big: 5000000000
+  huge: 18446744073709551615
+  negative: -5
+  ratio: 0.75
+  enormous: 1e300
+  exponent: 1e20
+  whole: 3.0
+  text: "lots"`))
+
+	t.Run("with values in range", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, int64(5000000000), c.GetInt64("limits.big"))
+		assert.Equal(t, int32(1024), c.GetInt32("limits.small"))
+		assert.Equal(t, uint(1024), c.GetUint("limits.small"))
+		assert.Equal(t, uint64(18446744073709551615), c.GetUint64("limits.huge"))
+		assert.Equal(t, float32(0.75), c.GetFloat32("limits.ratio"))
+	})
+
+	t.Run("with an int64 value read as int32", func(t *testing.T) {
+		t.Parallel()
+		_, err := c.GetInt32Strict("limits.big")
+		require.ErrorIs(t, err, config.ErrOutOfRange)
+		assert.Contains(t, err.Error(), "overflows int32")
+		assert.Zero(t, c.GetInt32("limits.big"))
+	})
+
+	t.Run("with a uint64 value read as int64", func(t *testing.T) {
+		t.Parallel()
+		_, err := c.GetInt64Strict("limits.huge")
+		require.ErrorIs(t, err, config.ErrOutOfRange)
+	})
+
+	t.Run("with a negative value read as unsigned", func(t *testing.T) {
+		t.Parallel()
+		_, err := c.GetUint64Strict("limits.negative")
+		require.Error(t, err)
+		assert.Zero(t, c.GetUint("limits.negative"))
+	})
+
+	t.Run("with a float64 value beyond uint64", func(t *testing.T) {
+		t.Parallel()
+		_, err := c.GetUint64Strict("limits.exponent")
+		require.ErrorIs(t, err, config.ErrOutOfRange)
+		assert.Zero(t, c.GetUint64("limits.exponent"))
+	})
+
+	t.Run("with a fractional value read as an integer", func(t *testing.T) {
+		t.Parallel()
+		_, err := c.GetInt64Strict("limits.ratio")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not a whole number")
+
+		_, err = c.GetUint64Strict("limits.ratio")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not a whole number")
+	})
+
+	t.Run("with a whole float64 value", func(t *testing.T) {
+		t.Parallel()
+		n, err := c.GetInt32Strict("limits.whole")
+		require.NoError(t, err)
+		assert.Equal(t, int32(3), n)
+
+		u, err := c.GetUintStrict("limits.whole")
+		require.NoError(t, err)
+		assert.Equal(t, uint(3), u)
+	})
+
+	t.Run("with a float64 value read as float32", func(t *testing.T) {
+		t.Parallel()
+		_, err := c.GetFloat32Strict("limits.enormous")
+		require.ErrorIs(t, err, config.ErrOutOfRange)
+	})
+
+	t.Run("with a non-numeric value", func(t *testing.T) {
+		t.Parallel()
+		_, err := c.GetInt64Strict("limits.text")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, config.ErrOutOfRange)
+	})
+
+	t.Run("with a missing key", func(t *testing.T) {
+		t.Parallel()
+		_, err := c.GetUintStrict("limits.missing")
+		require.ErrorIs(t, err, config.ErrKeyNotSet)
+		assert.Zero(t, c.GetUint("limits.missing"))
+	})
+}
+
+func TestContainer_SizedNumbers_Env(t *testing.T) {
+	t.Setenv("LIMITS_FROM_ENV", "3000000000")
+	l := log.New(io.Discard)
+	c := config.NewReaderContainer(l, "yaml", strings.NewReader(""))
+
+	assert.Equal(t, int64(3000000000), c.GetInt64("limits.from_env"))
+	assert.Equal(t, uint(3000000000), c.GetUint("limits.from_env"))
+	_, err := c.GetInt32Strict("limits.from_env")
+	require.ErrorIs(t, err, config.ErrOutOfRange)
+}
+
+func TestContainer_GetBytesBase64(t *testing.T) {
+	t.Parallel()
+	l := log.New(io.Discard)
+	c := config.NewReaderContainer(l, "yaml", strings.NewReader(`tls:
+  std: "aGVsbG8/d29ybGQ+"
+  url: "aGVsbG8_d29ybGQ-"
+  url_padded: "aGk="
+  invalid: "not*base64"`))
+
+	t.Run("with standard encoding", func(t *testing.T) {
+		t.Parallel()
+		b, err := c.GetBytesBase64("tls.std")
+		require.NoError(t, err)
+		assert.Equal(t, []byte("hello?world>"), b)
+	})
+
+	t.Run("with URL encoding", func(t *testing.T) {
+		t.Parallel()
+		b, err := c.GetBytesBase64URL("tls.url")
+		require.NoError(t, err)
+		assert.Equal(t, []byte("hello?world>"), b)
+
+		b, err = c.GetBytesBase64URL("tls.url_padded")
+		require.NoError(t, err)
+		assert.Equal(t, []byte("hi"), b)
+
+		_, err = c.GetBytesBase64("tls.url")
+		require.Error(t, err)
+	})
+
+	t.Run("with invalid input", func(t *testing.T) {
+		t.Parallel()
+		_, err := c.GetBytesBase64("tls.invalid")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not valid base64")
+	})
+
+	t.Run("with a missing key", func(t *testing.T) {
+		t.Parallel()
+		_, err := c.GetBytesBase64("tls.missing")
+		require.ErrorIs(t, err, config.ErrKeyNotSet)
+	})
+}
+
+func TestContainer_StrictTypes(t *testing.T) {
+	l := log.New(io.Discard)
+	c := config.NewStringContainer(l, "yaml", `release:
+  quoted: "1.0"
+  unquoted: 1.0
+  count: 3
+  huge: 18446744073709551615
+  name: "three"`)
+
+	t.Run("with a string written as a string", func(t *testing.T) {
+		s, err := c.GetStringStrict("release.quoted")
+		require.NoError(t, err)
+		assert.Equal(t, "1.0", s)
+	})
+
+	t.Run("with a string written as a float", func(t *testing.T) {
+		_, err := c.GetStringStrict("release.unquoted")
+		require.ErrorIs(t, err, config.ErrTypeMismatch)
+		assert.Equal(t, "1", c.GetString("release.unquoted"))
+	})
+
+	t.Run("with an int written as an int", func(t *testing.T) {
+		n, err := c.GetIntStrict("release.count")
+		require.NoError(t, err)
+		assert.Equal(t, 3, n)
+	})
+
+	t.Run("with an int written as a float or string", func(t *testing.T) {
+		_, err := c.GetIntStrict("release.unquoted")
+		require.ErrorIs(t, err, config.ErrTypeMismatch)
+
+		_, err = c.GetIntStrict("release.quoted")
+		require.ErrorIs(t, err, config.ErrTypeMismatch)
+
+		_, err = c.GetIntStrict("release.name")
+		require.ErrorIs(t, err, config.ErrTypeMismatch)
+	})
+
+	t.Run("with an int out of range", func(t *testing.T) {
+		_, err := c.GetIntStrict("release.huge")
+		require.ErrorIs(t, err, config.ErrOutOfRange)
+	})
+
+	t.Run("with a missing key", func(t *testing.T) {
+		_, err := c.GetStringStrict("release.missing")
+		require.ErrorIs(t, err, config.ErrKeyNotSet)
+	})
+
+	t.Run("with an int from the environment", func(t *testing.T) {
+		t.Setenv("RELEASE_REPLICAS", "5")
+		n, err := c.GetIntStrict("release.replicas")
+		require.NoError(t, err)
+		assert.Equal(t, 5, n)
+
+		t.Setenv("RELEASE_REPLICAS", "5.5")
+		_, err = c.GetIntStrict("release.replicas")
+		require.ErrorIs(t, err, config.ErrTypeMismatch)
+	})
+}
+
+func TestContainer_GetMapKeys(t *testing.T) {
+	t.Parallel()
+	l := log.New(io.Discard)
+	c := config.NewReaderContainer(l, "yaml", strings.NewReader(`enabled_features:
+  tracing: true
+  auth: true
+  logging: false
+name: "app"`))
+
+	t.Run("with a map value", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, []string{"auth", "logging", "tracing"}, c.GetMapKeys("enabled_features"))
+	})
+
+	t.Run("with a scalar value", func(t *testing.T) {
+		t.Parallel()
+		assert.Empty(t, c.GetMapKeys("name"))
+	})
+
+	t.Run("with a missing key", func(t *testing.T) {
+		t.Parallel()
+		keys := c.GetMapKeys("disabled_features")
+		assert.NotNil(t, keys)
+		assert.Empty(t, keys)
+	})
+}
+
+func TestContainer_GetStringFirst(t *testing.T) {
+	t.Parallel()
+	l := log.New(io.Discard)
+	c := config.NewReaderContainer(l, "yaml", strings.NewReader(`server:
+  listen: ":9090"
+legacy:
+  port: ":8080"`))
+
+	t.Run("with new key set", func(t *testing.T) {
+		t.Parallel()
+		v, k := c.GetStringFirst("server.listen", "legacy.port")
+		assert.Equal(t, ":9090", v)
+		assert.Equal(t, "server.listen", k)
+	})
+
+	t.Run("with only old key set", func(t *testing.T) {
+		t.Parallel()
+		v, k := c.GetStringFirst("server.addr", "legacy.port")
+		assert.Equal(t, ":8080", v)
+		assert.Equal(t, "legacy.port", k)
+	})
+
+	t.Run("with neither set", func(t *testing.T) {
+		t.Parallel()
+		v, k := c.GetStringFirst("server.addr", "legacy.addr")
+		assert.Empty(t, v)
+		assert.Empty(t, k)
+	})
+}
+
+func TestContainer_GetJSON(t *testing.T) {
+	t.Parallel()
+	l := log.New(io.Discard)
+	c := config.NewReaderContainer(l, "yaml", strings.NewReader(`plugin:
+  settings:
+    name: exporter
+    batch: 100
+    targets: [a, b]
+  enabled: true`))
+
+	t.Run("with nested object", func(t *testing.T) {
+		t.Parallel()
+		raw, err := c.GetJSON("plugin.settings")
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"name": "exporter", "batch": 100, "targets": ["a", "b"]}`, string(raw))
+	})
+
+	t.Run("with scalar", func(t *testing.T) {
+		t.Parallel()
+		raw, err := c.GetJSON("plugin.enabled")
+		require.NoError(t, err)
+		assert.True(t, json.Valid(raw))
+		assert.JSONEq(t, `true`, string(raw))
+	})
+
+	t.Run("with missing key", func(t *testing.T) {
+		t.Parallel()
+		_, err := c.GetJSON("plugin.missing")
+		require.ErrorIs(t, err, config.ErrKeyNotSet)
+	})
+}
@@ -0,0 +1,198 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/go-errors/errors"
+	"github.com/spf13/afero"
+)
+
+// watchConfig monitor the changes in all loaded config files.
+// The directories holding the files are watched, rather than the files themselves, so that atomic saves
+// which replace a file are picked up. Only files on the OS filesystem are watched, as paths on any other
+// afero.Fs don't name the OS directories that fsnotify would watch.
+func (c *Container) watchConfig() {
+	if _, ok := c.fs.(*afero.OsFs); !ok {
+		return
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		c.logger.Warn("unable to create config watcher", "error", err)
+
+		return
+	}
+
+	if c.watchDirs(watcher, c.files) == 0 {
+		_ = watcher.Close()
+
+		return
+	}
+
+	c.watcher = watcher
+	go c.watchLoop(watcher)
+}
+
+// watchDirs add the directories holding files to watcher, returning how many were added.
+func (c *Container) watchDirs(watcher *fsnotify.Watcher, files []string) int {
+	watched := 0
+	dirs := make(map[string]bool)
+	for _, f := range files {
+		c.trackSymlink(f)
+
+		dir := filepath.Dir(absPath(f))
+		if dirs[dir] {
+			continue
+		}
+		dirs[dir] = true
+
+		if err := watcher.Add(dir); err != nil {
+			c.logger.Warn("unable to watch config directory", "dir", dir, "error", err)
+
+			continue
+		}
+		watched++
+	}
+
+	return watched
+}
+
+// watchForCreation treat paths as the container's files, including those absent at load, so that creating
+// one later triggers a reload merging it in its place among paths. This suits mounted config, such as
+// Kubernetes ConfigMaps, that may appear after startup. The directories must already exist to be watched.
+func (c *Container) watchForCreation(paths []string) {
+	c.files = paths
+
+	if c.watcher != nil {
+		c.watchDirs(c.watcher, paths)
+
+		return
+	}
+
+	c.watchConfig()
+}
+
+// Close stop watching the container's files, cancel any pending reload and detach all observers.
+// Containers created from files should be closed once no longer needed so that the watcher's file
+// descriptors and goroutine are released. Closing an already closed container is a no-op.
+func (c *Container) Close() error {
+	c.reloadMu.Lock()
+	if c.reloadTimer != nil {
+		c.reloadTimer.Stop()
+		c.reloadTimer = nil
+	}
+	c.reloadMu.Unlock()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.observers = make([]Observable, 0)
+	c.subtreeObservers = nil
+	c.eventObservers = nil
+
+	if c.watcher == nil {
+		return nil
+	}
+
+	watcher := c.watcher
+	c.watcher = nil
+	if err := watcher.Close(); err != nil {
+		return errors.Wrap(err, 0)
+	}
+
+	return nil
+}
+
+// watchLoop dispatch events for loaded files until the watcher is closed.
+func (c *Container) watchLoop(watcher *fsnotify.Watcher) {
+	for {
+		select {
+		case e, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+
+			loaded := c.isLoadedFile(e.Name) && (e.Has(fsnotify.Write) || e.Has(fsnotify.Create))
+			if !loaded && !c.symlinkSwapped() {
+				continue
+			}
+
+			c.debounceReload(func() {
+				c.handleConfigChange(e)
+			})
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+
+			c.logger.Warn("config watcher error", "error", err)
+		}
+	}
+}
+
+// trackSymlink record the target of f if it is a symlink. Kubernetes mounts ConfigMap keys as symlinks
+// through a `..data` symlink that is swapped atomically on update, which produces events for `..data` in
+// the watched directory rather than for f itself, so the target is compared on each event instead.
+func (c *Container) trackSymlink(f string) {
+	f = absPath(f)
+	info, err := os.Lstat(f)
+	if err != nil || info.Mode()&os.ModeSymlink == 0 {
+		return
+	}
+
+	target, err := filepath.EvalSymlinks(f)
+	if err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.symlinkTargets == nil {
+		c.symlinkTargets = make(map[string]string)
+	}
+	c.symlinkTargets[f] = target
+}
+
+// symlinkSwapped reports whether any tracked symlinked file now resolves to a different target, recording
+// the new targets. Files that can't be resolved, such as mid-swap, are left for a later event.
+func (c *Container) symlinkSwapped() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	swapped := false
+	for f, previous := range c.symlinkTargets {
+		target, err := filepath.EvalSymlinks(f)
+		if err != nil || target == previous {
+			continue
+		}
+
+		c.symlinkTargets[f] = target
+		swapped = true
+	}
+
+	return swapped
+}
+
+// isLoadedFile reports whether name refers to one of the container's loaded files.
+func (c *Container) isLoadedFile(name string) bool {
+	name = absPath(name)
+	for _, f := range c.files {
+		if absPath(f) == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// absPath clean p and make it absolute where possible.
+func absPath(p string) string {
+	if abs, err := filepath.Abs(p); err == nil {
+		return abs
+	}
+
+	return filepath.Clean(p)
+}
@@ -0,0 +1,228 @@
+package config_test
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/phpboyscout/config"
+)
+
+func TestContainer_WatchMultipleFiles(t *testing.T) {
+	t.Parallel()
+	logger := log.New(io.Discard)
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.yml")
+	second := filepath.Join(dir, "second.yml")
+	require.NoError(t, os.WriteFile(first, []byte(firstMockFilesYaml), 0o600))
+	require.NoError(t, os.WriteFile(second, []byte(secondMockFilesYaml), 0o600))
+
+	c := config.NewFilesContainer(logger, afero.NewOsFs(), first, second)
+	require.Equal(t, "value2", c.GetString("yaml.key"))
+
+	var observed int32
+	var observedValue atomic.Value
+	c.AddObserverFunc(func(c config.Containable, _ chan error) {
+		atomic.AddInt32(&observed, 1)
+		observedValue.Store(c.GetString("yaml.key"))
+	})
+
+	require.NoError(t, os.WriteFile(second, []byte("yaml:\n  key: \"updated\""), 0o600))
+
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&observed) == 1 }, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "updated", observedValue.Load())
+	assert.Equal(t, "updated", c.GetString("yaml.key"))
+	assert.True(t, c.GetBool("yaml.bool"))
+	assert.Empty(t, c.GetString("yaml.more.key2"))
+}
+
+func TestContainer_WatchCreatedFile(t *testing.T) {
+	t.Parallel()
+	logger := log.New(io.Discard)
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.yml")
+	override := filepath.Join(dir, "override.yml")
+	require.NoError(t, os.WriteFile(base, []byte(firstMockFilesYaml), 0o600))
+
+	c, err := config.Load([]string{base, override}, afero.NewOsFs(), logger, false)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.(*config.Container).Close() })
+	require.Equal(t, "value", c.GetString("yaml.key"))
+
+	var observed int32
+	c.AddObserverFunc(func(_ config.Containable, _ chan error) {
+		atomic.AddInt32(&observed, 1)
+	})
+
+	require.NoError(t, os.WriteFile(override, []byte("yaml:\n  key: \"created\""), 0o600))
+
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&observed) >= 1 }, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "created", c.GetString("yaml.key"))
+	assert.True(t, c.GetBool("yaml.bool"))
+}
+
+func TestLoadLayered_WatchCreatedLayer(t *testing.T) {
+	t.Parallel()
+	logger := log.New(io.Discard)
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(firstMockFilesYaml), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.dev.yaml"), []byte("yaml:\n  key: \"dev\""), 0o600))
+
+	c, err := config.LoadLayered(dir, "config", "dev", afero.NewOsFs(), logger)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.(*config.Container).Close() })
+	require.Equal(t, "dev", c.GetString("yaml.key"))
+
+	var observed int32
+	c.AddObserverFunc(func(_ config.Containable, _ chan error) {
+		atomic.AddInt32(&observed, 1)
+	})
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.dev.local.yaml"), []byte("yaml:\n  key: \"local\""), 0o600))
+
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&observed) >= 1 }, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "local", c.GetString("yaml.key"))
+	assert.True(t, c.GetBool("yaml.bool"))
+}
+
+func TestContainer_WatchConfigMapSymlinkSwap(t *testing.T) {
+	t.Parallel()
+	logger := log.New(io.Discard)
+	dir := t.TempDir()
+
+	// mirror the layout of a Kubernetes ConfigMap volume:
+	// config.yml -> ..data/config.yml, ..data -> ..<timestamp>
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "..2024_01_01"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "..2024_01_01", "config.yml"), []byte(firstMockFilesYaml), 0o600))
+	require.NoError(t, os.Symlink("..2024_01_01", filepath.Join(dir, "..data")))
+	require.NoError(t, os.Symlink(filepath.Join("..data", "config.yml"), filepath.Join(dir, "config.yml")))
+
+	c := config.NewFilesContainer(logger, afero.NewOsFs(), filepath.Join(dir, "config.yml"))
+	t.Cleanup(func() { _ = c.Close() })
+	require.Equal(t, "value", c.GetString("yaml.key"))
+
+	var observed int32
+	c.AddObserverFunc(func(_ config.Containable, _ chan error) {
+		atomic.AddInt32(&observed, 1)
+	})
+
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "..2024_01_02"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "..2024_01_02", "config.yml"), []byte("yaml:\n  key: \"swapped\""), 0o600))
+	require.NoError(t, os.Symlink("..2024_01_02", filepath.Join(dir, "..data_tmp")))
+	require.NoError(t, os.Rename(filepath.Join(dir, "..data_tmp"), filepath.Join(dir, "..data")))
+	require.NoError(t, os.RemoveAll(filepath.Join(dir, "..2024_01_01")))
+
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&observed) >= 1 }, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "swapped", c.GetString("yaml.key"))
+}
+
+// reloadStressYaml config rewritten during TestContainer_GetDuringReload, formatted with the revision.
+var reloadStressYaml = `yaml:
+  key: "value%[1]d"
+  bool: true
+  size: "%[1]dMB"
+  url: "http://host/%[1]d"
+  level: "info"
+  ref: "${yaml.key}"
+  labels:
+    rev: "r%[1]d"
+    key: "${config:yaml.key}"`
+
+func TestContainer_GetDuringReload(t *testing.T) {
+	t.Parallel()
+	logger := log.New(io.Discard)
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.yml")
+	require.NoError(t, os.WriteFile(file, []byte(fmt.Sprintf(reloadStressYaml, -1)), 0o600))
+
+	c := config.NewFilesContainer(logger, afero.NewOsFs(), file)
+
+	var reloads int32
+	c.AddObserverFunc(func(config.Containable, chan error) {
+		atomic.AddInt32(&reloads, 1)
+	})
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+					_ = c.GetString("yaml.key")
+					_ = c.GetBool("yaml.bool")
+					_, _ = c.GetBytes("yaml.size")
+					_, _ = c.GetURL("yaml.url")
+					_, _ = c.GetEnum("yaml.level", []string{"debug", "info"})
+					_, _ = c.GetStringMapString("yaml.labels")
+					_ = c.GetStringExpanded("yaml.ref")
+					_, _ = c.GetStringDecrypted("yaml.key")
+					var labels map[string]string
+					_ = c.UnmarshalKey("yaml.labels", &labels)
+					_, _ = c.SubPath("yaml.labels")
+					_ = c.ToJSON()
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, os.WriteFile(file, []byte(fmt.Sprintf(reloadStressYaml, i)), 0o600))
+		time.Sleep(150 * time.Millisecond)
+	}
+
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&reloads) > 0 }, 2*time.Second, 10*time.Millisecond)
+	close(done)
+	wg.Wait()
+
+	assert.Eventually(t, func() bool { return c.GetString("yaml.key") == "value4" }, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestContainer_Close(t *testing.T) {
+	ignore := goleak.IgnoreCurrent()
+
+	logger := log.New(io.Discard)
+	file := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(file, []byte(firstMockFilesYaml), 0o600))
+
+	c := config.NewFilesContainer(logger, afero.NewOsFs(), file)
+	c.AddObserverFunc(func(config.Containable, chan error) {})
+
+	require.NoError(t, c.Close())
+	assert.Empty(t, c.GetObservers())
+	assert.Equal(t, "value", c.GetString("yaml.key"))
+	require.NoError(t, c.Close())
+
+	goleak.VerifyNone(t, ignore)
+}
+
+func TestContainer_NoWatcherForMemFs(t *testing.T) {
+	ignore := goleak.IgnoreCurrent()
+
+	logger := log.New(io.Discard)
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "config.yml", []byte(firstMockFilesYaml), 0o644))
+
+	c := config.NewFilesContainer(logger, fs, "config.yml")
+	assert.Equal(t, "value", c.GetString("yaml.key"))
+
+	_, err := config.Load([]string{"config.yml", "missing.yml"}, fs, logger, false)
+	require.NoError(t, err)
+
+	goleak.VerifyNone(t, ignore)
+}